@@ -0,0 +1,100 @@
+package interactor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Capabilities an account can have, as listed by the access endpoint
+const (
+	CapabilityContestStart = "contest_start"
+	CapabilityContestThaw  = "contest_thaw"
+	CapabilityTeamSubmit   = "team_submit"
+	CapabilityTeamClar     = "team_clar"
+	CapabilityProxySubmit  = "proxy_submit"
+	CapabilityProxyClar    = "proxy_clar"
+	CapabilityAdminSubmit  = "admin_submit"
+	CapabilityAdminClar    = "admin_clar"
+)
+
+type (
+	// Access describes what the current account can do and see in a contest
+	Access struct {
+		Capabilities []string         `json:"capabilities"`
+		Endpoints    []AccessEndpoint `json:"endpoints"`
+	}
+
+	// AccessEndpoint lists the properties of the objects of an endpoint that are visible to the current account
+	AccessEndpoint struct {
+		Type       string   `json:"type"`
+		Properties []string `json:"properties"`
+	}
+)
+
+// -- Access implementation
+
+func (a Access) FromJSON(data []byte) (ApiType, error) {
+	err := json.Unmarshal(data, &a)
+	return a, err
+}
+
+func (a Access) String() string {
+	endpoints := make([]string, len(a.Endpoints))
+	for k, e := range a.Endpoints {
+		endpoints[k] = e.Type
+	}
+
+	return fmt.Sprintf(`
+capabilities: %v
+   endpoints: %v
+`, a.Capabilities, endpoints)
+}
+
+func (a Access) InContest() bool {
+	return true
+}
+
+func (a Access) Path() string {
+	return "access"
+}
+
+func (a Access) Generate() ApiType {
+	return Access{}
+}
+
+// Can returns whether the account has capability
+func (a Access) Can(capability string) bool {
+	return containsString(a.Capabilities, capability)
+}
+
+// CanSubmit returns whether the account can submit, either as a team, on behalf of teams or as an admin
+func (a Access) CanSubmit() bool {
+	return a.Can(CapabilityTeamSubmit) || a.Can(CapabilityProxySubmit) || a.Can(CapabilityAdminSubmit)
+}
+
+// CanClarify returns whether the account can post clarifications, either as a team, on behalf of teams or as an admin
+func (a Access) CanClarify() bool {
+	return a.Can(CapabilityTeamClar) || a.Can(CapabilityProxyClar) || a.Can(CapabilityAdminClar)
+}
+
+// CanSee returns whether the endpoint with the given type, such as "submissions", is visible
+func (a Access) CanSee(endpoint string) bool {
+	_, ok := a.endpoint(endpoint)
+	return ok
+}
+
+// CanSeeProperty returns whether the property of the objects of the given endpoint is visible
+func (a Access) CanSeeProperty(endpoint, property string) bool {
+	e, ok := a.endpoint(endpoint)
+	return ok && containsString(e.Properties, property)
+}
+
+func (a Access) endpoint(endpoint string) (AccessEndpoint, bool) {
+	for _, e := range a.Endpoints {
+		if e.Type == endpoint {
+			return e, true
+		}
+	}
+
+	return AccessEndpoint{}, false
+}
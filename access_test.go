@@ -0,0 +1,47 @@
+package interactor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccess(t *testing.T) {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	access := func(t *testing.T, username, password string) Access {
+		api, err := ContestInteractor(server.URL, username, password, "nwerc18", false)
+		assert.Nil(t, err)
+
+		access, err := api.Access()
+		assert.Nil(t, err)
+		return access
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		a := access(t, "", "")
+		assert.False(t, a.CanSubmit())
+		assert.False(t, a.CanClarify())
+		assert.True(t, a.CanSee("problems"))
+		assert.False(t, a.CanSee("submissions"))
+	})
+
+	t.Run("team", func(t *testing.T) {
+		a := access(t, "team", "team")
+		assert.True(t, a.Can(CapabilityTeamSubmit))
+		assert.False(t, a.Can(CapabilityProxySubmit))
+		assert.True(t, a.CanSubmit())
+		assert.True(t, a.CanClarify())
+		assert.True(t, a.CanSee("submissions"))
+		assert.True(t, a.CanSeeProperty("submissions", "team_id"))
+		assert.False(t, a.CanSeeProperty("submissions", "color"))
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		a := access(t, "admin", "admin")
+		assert.True(t, a.Can(CapabilityProxySubmit))
+		assert.True(t, a.Can(CapabilityAdminClar))
+		assert.True(t, a.CanSee("contest"))
+	})
+}
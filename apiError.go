@@ -0,0 +1,100 @@
+package interactor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+type (
+	// APIError is returned when the API responds with a statuscode indicating failure. It unwraps to one of the
+	// sentinel errors below, depending on the statuscode, such that it can be used with errors.Is.
+	APIError struct {
+		StatusCode int
+		Method     string
+		URL        string
+
+		// Details is the error as sent by the server, nil when the body could not be parsed
+		Details *Error
+		// Body is the raw body of the response, only set when it could not be parsed
+		Body []byte
+	}
+)
+
+var (
+	ErrBadRequest   = errors.New("bad request")            // 400
+	ErrUnauthorized = errors.New("request not authorized") // 401
+	ErrForbidden    = errors.New("forbidden")              // 403
+	ErrNotFound     = errors.New("object not found")       // 404
+	ErrConflict     = errors.New("conflict")               // 409
+)
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error for %s %s: ", e.Method, e.URL)
+	if err := e.Unwrap(); err != nil {
+		msg += err.Error()
+	} else {
+		msg += fmt.Sprintf("invalid statuscode received: %d", e.StatusCode)
+	}
+
+	if e.Details != nil && e.Details.Message != "" {
+		msg += fmt.Sprintf("; %s (error code %d)", e.Details.Message, e.Details.Code)
+	}
+
+	return msg
+}
+
+// Unwrap returns the sentinel error belonging to the statuscode, nil if there is none
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	}
+
+	return nil
+}
+
+// Temporary reports whether the request may succeed when it is retried later
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= http.StatusInternalServerError
+}
+
+// responseToError converts a response with a statuscode indicating failure to an *APIError, nil is returned for
+// successful responses. The body of a failed response is consumed.
+func responseToError(r *http.Response) error {
+	if r.StatusCode >= 200 && r.StatusCode < 300 {
+		return nil
+	}
+
+	e := &APIError{StatusCode: r.StatusCode}
+	if r.Request != nil {
+		e.Method = r.Request.Method
+		e.URL = r.Request.URL.String()
+	}
+
+	// Read the contents, a failure to do so still results in an APIError
+	bts, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return e
+	}
+
+	var details Error
+	if err := json.Unmarshal(bts, &details); err != nil || (details.Code == 0 && details.Message == "") {
+		e.Body = bts
+		return e
+	}
+
+	e.Details = &details
+	return e
+}
@@ -0,0 +1,67 @@
+package interactor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/teams/unknown":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404,"message":"team not found"}`))
+		case "/contests/test/teams":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`<html>login required</html>`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ser.Close()
+
+	api, err := ContestInteractor(ser.URL, "", "", "test", false)
+	assert.Nil(t, err)
+
+	t.Run("not-found", func(t *testing.T) {
+		_, err := api.TeamById("unknown")
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.False(t, errors.Is(err, ErrUnauthorized))
+
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.EqualValues(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.EqualValues(t, http.MethodGet, apiErr.Method)
+		assert.EqualValues(t, ser.URL+"/contests/test/teams/unknown", apiErr.URL)
+		assert.EqualValues(t, "team not found", apiErr.Details.Message)
+		assert.Nil(t, apiErr.Body)
+		assert.Contains(t, err.Error(), "team not found")
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		_, err := api.Teams()
+		assert.True(t, errors.Is(err, ErrUnauthorized))
+
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Nil(t, apiErr.Details)
+		assert.EqualValues(t, "<html>login required</html>", apiErr.Body)
+		assert.False(t, apiErr.Temporary())
+	})
+
+	t.Run("other", func(t *testing.T) {
+		_, err := api.Problems()
+
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Nil(t, apiErr.Unwrap())
+		assert.True(t, apiErr.Temporary())
+	})
+
+}
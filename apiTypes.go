@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -46,14 +47,21 @@ type (
 		files []localFileData
 	}
 
+	// FileReference refers to a file served by the API, such as a logo or the files of a submission. Files that are
+	// sent to the API, such as the files of a new submission, are included in Data instead.
 	FileReference struct {
-		Href   string             `json:"href,omitempty"`
-		Mime   string             `json:"mime,omitempty"`
-		Width  int                `json:"width,omitempty"`
-		Height int                `json:"height,omitempty"`
-		Data   LocalFileReference `json:"data,omitempty"`
+		Href     string             `json:"href,omitempty"`
+		Filename string             `json:"filename,omitempty"`
+		Mime     string             `json:"mime,omitempty"`
+		Width    int                `json:"width,omitempty"`
+		Height   int                `json:"height,omitempty"`
+		Data     LocalFileReference `json:"data,omitempty"`
 	}
 
+	// FileReferences is the list of files of a property, such as the logos of an organization. Some providers give
+	// a single file as an object instead of a list, which is decoded as a list of one file.
+	FileReferences []FileReference
+
 	// TODO add omitempty to appropriate keys, ensure that "Time"s that are omitempty are references to ensure
 	//      the time is actually omitted. This is due to ApiTime is based on time.Time which is almost always a
 	//      non-empty struct.
@@ -66,24 +74,30 @@ type (
 		Duration                 ApiRelTime `json:"duration"`
 		ScoreboardFreezeDuration ApiRelTime `json:"scoreboard_freeze_duration,omitempty"`
 		CountdownTime            ApiRelTime `json:"countdown_pause_time,omitempty"`
+		// PenaltyTime is the number of minutes of penalty for every rejected submission on a solved problem, nil when
+		// the contest does not specify it
+		PenaltyTime *int           `json:"penalty_time,omitempty"`
+		Banner      FileReferences `json:"banner,omitempty"`
+		Logo        FileReferences `json:"logo,omitempty"`
 	}
 
 	Problem struct {
-		Id      string `json:"id"`
-		Label   string `json:"label"`
-		Name    string `json:"name"`
-		Ordinal int    `json:"ordinal"`
+		Id        string         `json:"id"`
+		Label     string         `json:"label"`
+		Name      string         `json:"name"`
+		Ordinal   int            `json:"ordinal"`
+		Statement FileReferences `json:"statement,omitempty"`
 	}
 
 	Submission struct {
-		Id          string          `json:"id,omitempty"`
-		LanguageId  string          `json:"language_id"`
-		Time        *ApiTime        `json:"time,omitempty"`
-		ContestTime ApiRelTime      `json:"contest_time,omitempty"`
-		TeamId      string          `json:"team_id,omitempty"`
-		ProblemId   string          `json:"problem_id,omitempty"`
-		EntryPoint  string          `json:"entry_point,omitempty"`
-		Files       []FileReference `json:"files,omitempty"`
+		Id          string         `json:"id,omitempty"`
+		LanguageId  string         `json:"language_id"`
+		Time        *ApiTime       `json:"time,omitempty"`
+		ContestTime ApiRelTime     `json:"contest_time,omitempty"`
+		TeamId      string         `json:"team_id,omitempty"`
+		ProblemId   string         `json:"problem_id,omitempty"`
+		EntryPoint  string         `json:"entry_point,omitempty"`
+		Files       FileReferences `json:"files,omitempty"`
 	}
 
 	JudgementType struct {
@@ -104,6 +118,34 @@ type (
 		MaxRunTime       float32    `json:"max_run_time,omitempty"`
 	}
 
+	// Run is the result of running a submission on a single test case of a judgement
+	Run struct {
+		Id              string     `json:"id"`
+		JudgementId     string     `json:"judgement_id"`
+		Ordinal         int        `json:"ordinal"`
+		JudgementTypeId string     `json:"judgement_type_id"`
+		Time            *ApiTime   `json:"time,omitempty"`
+		ContestTime     ApiRelTime `json:"contest_time"`
+		RunTime         float64    `json:"run_time,omitempty"`
+	}
+
+	Award struct {
+		Id       string   `json:"id"`
+		Citation string   `json:"citation"`
+		TeamIds  []string `json:"team_ids"`
+	}
+
+	// Commentary is a message about the contest, referring to the teams, problems and submissions it is about
+	Commentary struct {
+		Id            string     `json:"id,omitempty"`
+		Time          *ApiTime   `json:"time,omitempty"`
+		ContestTime   ApiRelTime `json:"contest_time,omitempty"`
+		Message       string     `json:"message"`
+		TeamIds       []string   `json:"team_ids,omitempty"`
+		ProblemIds    []string   `json:"problem_ids,omitempty"`
+		SubmissionIds []string   `json:"submission_ids,omitempty"`
+	}
+
 	Clarification struct {
 		Id          string     `json:"id,omitempty"`
 		FromTeamId  string     `json:"from_team_id,omitempty"`
@@ -132,33 +174,36 @@ type (
 	}
 
 	Organization struct {
-		Id             string `json:"id"`
-		ICPCId         string `json:"icpc_id"`
-		Name           string `json:"name"`
-		FormalName     string `json:"formal_name"`
-		Country        string `json:"country"`
-		URL            string `json:"url"`
-		TwitterHashtag string `json:"twitter_hashtag"`
+		Id             string         `json:"id"`
+		ICPCId         string         `json:"icpc_id"`
+		Name           string         `json:"name"`
+		FormalName     string         `json:"formal_name"`
+		Country        string         `json:"country"`
+		URL            string         `json:"url"`
+		TwitterHashtag string         `json:"twitter_hashtag"`
+		Logo           FileReferences `json:"logo,omitempty"`
 	}
 
 	Team struct {
-		Id             string   `json:"id"`
-		ICPCId         string   `json:"icpc_id"`
-		Name           string   `json:"name"`
-		DisplayName    string   `json:"display_name"`
-		GroupIds       []string `json:"group_ids"`
-		OrganizationId string   `json:"organization_id"`
+		Id             string         `json:"id"`
+		ICPCId         string         `json:"icpc_id"`
+		Name           string         `json:"name"`
+		DisplayName    string         `json:"display_name"`
+		GroupIds       []string       `json:"group_ids"`
+		OrganizationId string         `json:"organization_id"`
+		Photo          FileReferences `json:"photo,omitempty"`
 	}
 
 	Person struct {
-		Id     string `json:"id"`
-		ICPCId string `json:"icpc_id,omitempty"`
-		Name   string `json:"name"`
-		Title  string `json:"title,omitempty"`
-		Email  string `json:"email,omitempty"`
-		Sex    string `json:"sex,omitempty"`
-		Role   string `json:"role,omitempty"`
-		TeamId string `json:"team_id,omitempty"`
+		Id     string         `json:"id"`
+		ICPCId string         `json:"icpc_id,omitempty"`
+		Name   string         `json:"name"`
+		Title  string         `json:"title,omitempty"`
+		Email  string         `json:"email,omitempty"`
+		Sex    string         `json:"sex,omitempty"`
+		Role   string         `json:"role,omitempty"`
+		TeamId string         `json:"team_id,omitempty"`
+		Photo  FileReferences `json:"photo,omitempty"`
 	}
 
 	Account struct {
@@ -185,6 +230,31 @@ func (c Contest) FromJSON(data []byte) (ApiType, error) {
 	return c, err
 }
 
+func (c *Contest) UnmarshalJSON(data []byte) error {
+	type plain Contest
+	var v struct {
+		plain
+		PenaltyTime json.RawMessage `json:"penalty_time"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*c = Contest(v.plain)
+	c.PenaltyTime = nil
+	if len(v.PenaltyTime) > 0 && !bytes.Equal(v.PenaltyTime, []byte("null")) {
+		penaltyTime, err := decodeMinutes(v.PenaltyTime)
+		if err != nil {
+			return fmt.Errorf("invalid penalty time; %w", err)
+		}
+
+		c.PenaltyTime = &penaltyTime
+	}
+
+	return nil
+}
+
 func (c Contest) String() string {
 	// TODO format the starttime and duration
 	return fmt.Sprintf(`
@@ -324,6 +394,63 @@ start contest time: %v
 `, j.Id, j.SubmissionId, j.JudgementTypeId, j.StartContestTime, j.EndContestTime)
 }
 
+// -- Run implementation
+
+func (r Run) FromJSON(data []byte) (ApiType, error) {
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r Run) InContest() bool {
+	return true
+}
+
+func (r Run) Path() string {
+	return "runs"
+}
+
+func (r Run) Generate() ApiType {
+	return Run{}
+}
+
+func (r Run) String() string {
+	return fmt.Sprintf(`
+                id: %v
+      judgement id: %v
+           ordinal: %v
+ judgement type id: %v
+      contest time: %v
+          run time: %v
+`, r.Id, r.JudgementId, r.Ordinal, r.JudgementTypeId, r.ContestTime, r.RunTime)
+}
+
+// -- Award implementation
+
+func (a Award) FromJSON(data []byte) (ApiType, error) {
+	err := json.Unmarshal(data, &a)
+	return a, err
+}
+
+func (a Award) InContest() bool {
+	return true
+}
+
+func (a Award) Path() string {
+	return "awards"
+}
+
+func (a Award) Generate() ApiType {
+	return Award{}
+}
+
+func (a Award) String() string {
+	return fmt.Sprintf(`
+      id: %v
+citation: %v
+team ids: %v
+`, a.Id, a.Citation, a.TeamIds)
+}
+
 // -- Group implementation
 
 func (g Group) FromJSON(data []byte) (ApiType, error) {
@@ -387,6 +514,33 @@ func (t Team) FromJSON(data []byte) (ApiType, error) {
 	return t, err
 }
 
+func (t *Team) UnmarshalJSON(data []byte) error {
+	type plain Team
+	var v struct {
+		plain
+		GroupIds json.RawMessage `json:"group_ids"`
+		// Some providers give the single group of a team instead of a list
+		GroupId *Identifier `json:"group_id"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	groupIds, err := decodeIdentifiers(v.GroupIds)
+	if err != nil {
+		return fmt.Errorf("invalid group ids; %w", err)
+	}
+
+	*t = Team(v.plain)
+	t.GroupIds = groupIds
+	if t.GroupIds == nil && v.GroupId != nil && *v.GroupId != "" {
+		t.GroupIds = []string{string(*v.GroupId)}
+	}
+
+	return nil
+}
+
 func (t Team) InContest() bool {
 	return true
 }
@@ -439,6 +593,37 @@ func (c Clarification) String() string {
 `, c.Id, c.FromTeamId, c.ToTeamId, c.ReplyToId, c.ProblemId, c.Text, c.Time, c.ContestTime)
 }
 
+// -- Commentary implementation
+
+func (c Commentary) FromJSON(data []byte) (ApiType, error) {
+	err := json.Unmarshal(data, &c)
+	return c, err
+}
+
+func (c Commentary) InContest() bool {
+	return true
+}
+
+func (c Commentary) Path() string {
+	return "commentary"
+}
+
+func (c Commentary) Generate() ApiType {
+	return Commentary{}
+}
+
+func (c Commentary) String() string {
+	return fmt.Sprintf(`
+            id: %v
+       message: %v
+      team ids: %v
+   problem ids: %v
+submission ids: %v
+          time: %v
+  contest time: %v
+`, c.Id, c.Message, c.TeamIds, c.ProblemIds, c.SubmissionIds, c.Time, c.ContestTime)
+}
+
 // -- Language implementation
 
 func (l Language) FromJSON(data []byte) (ApiType, error) {
@@ -574,6 +759,10 @@ func (a *ApiRelTime) UnmarshalJSON(b []byte) (err error) {
 	}
 	re := regexp.MustCompile("(-?[0-9]{1,2}):([0-9]{2}):([0-9]{2})(.([0-9]{3}))?")
 	sm := re.FindStringSubmatch(data)
+	if sm == nil {
+		return fmt.Errorf("can not parse relative time: %s", data)
+	}
+
 	h, err := strconv.ParseInt(sm[1], 10, 64)
 	if err != nil {
 		return err
@@ -597,11 +786,33 @@ func (a *ApiRelTime) UnmarshalJSON(b []byte) (err error) {
 		}
 	}
 
-	*a = ApiRelTime(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond)
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond
+	if strings.HasPrefix(sm[1], "-") {
+		// The sign applies to the time as a whole, not only to the hours
+		d = -(time.Duration(-h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond)
+	}
+
+	*a = ApiRelTime(d)
 
 	return
 }
 
+func (a ApiRelTime) MarshalJSON() ([]byte, error) {
+	d := time.Duration(a)
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	ms := (d % time.Second) / time.Millisecond
+
+	return []byte(fmt.Sprintf(`"%s%d:%02d:%02d.%03d"`, sign, h, m, s, ms)), nil
+}
+
 // -- ApiRelTime implementation
 
 func (a ApiRelTime) String() string {
@@ -662,11 +873,93 @@ func (a ApiTime) AddDuration(d ApiRelTime) ApiTime {
 // -- Identifier implementation
 
 func (i *Identifier) UnmarshalJSON(bts []byte) error {
-	// It is expected to be a string, possible embedded in quotes
+	// It is expected to be a string, but some versions and providers use numbers or null
+	var s string
+	if err := json.Unmarshal(bts, &s); err == nil {
+		*i = Identifier(s)
+		return nil
+	}
+
+	if string(bts) == "null" {
+		*i = ""
+		return nil
+	}
+
 	*i = Identifier(strings.Trim(string(bts), "\"'"))
 	return nil
 }
 
+// -- FileReference implementation
+
+func (f *FileReferences) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var ref FileReference
+		if err := json.Unmarshal(data, &ref); err != nil {
+			return err
+		}
+
+		*f = FileReferences{ref}
+		return nil
+	}
+
+	var refs []FileReference
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return err
+	}
+
+	*f = refs
+	return nil
+}
+
+func (f FileReference) MarshalJSON() ([]byte, error) {
+	// Data is only included when there are files to send, a LocalFileReference is never empty for omitempty
+	type plain FileReference
+	v := struct {
+		plain
+		Data *LocalFileReference `json:"data,omitempty"`
+	}{plain: plain(f)}
+
+	if len(f.Data.files) > 0 {
+		v.Data = &f.Data
+	}
+
+	return json.Marshal(v)
+}
+
+// BestFile returns the reference in refs that fits width and height best, which is the smallest image that is at
+// least as large or otherwise the largest image. References without a size are only returned when none of them have
+// one, in which case the first reference is returned. False is returned when refs is empty.
+func BestFile(refs []FileReference, width, height int) (FileReference, bool) {
+	if len(refs) == 0 {
+		return FileReference{}, false
+	}
+
+	fits := func(f FileReference) bool { return f.Width >= width && f.Height >= height }
+	area := func(f FileReference) int { return f.Width * f.Height }
+
+	var best *FileReference
+	for k := range refs {
+		f := &refs[k]
+		switch {
+		case f.Width == 0 || f.Height == 0:
+			continue
+		case best == nil:
+			best = f
+		case fits(*f) && (!fits(*best) || area(*f) < area(*best)):
+			best = f
+		case !fits(*f) && !fits(*best) && area(*f) > area(*best):
+			best = f
+		}
+	}
+
+	if best == nil {
+		return refs[0], true
+	}
+
+	return *best, true
+}
+
 // -- LocalFileReference implementation
 
 func (r *LocalFileReference) FromFile(file *os.File) error {
@@ -698,6 +991,37 @@ func (r *LocalFileReference) FromString(filename, body string) error {
 }
 
 func (r LocalFileReference) MarshalJSON() ([]byte, error) {
+	data, err := r.Zip()
+	if err != nil {
+		return nil, err
+	}
+
+	// Base64 encode the zipped contents
+	result := base64.StdEncoding.EncodeToString(data)
+	return json.Marshal(result)
+}
+
+func (r *LocalFileReference) UnmarshalJSON(bts []byte) error {
+	var encoded string
+	if err := json.Unmarshal(bts, &encoded); err != nil {
+		return err
+	}
+
+	r.files = nil
+	if encoded == "" {
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	return r.FromZip(data)
+}
+
+// Zip returns the files as a zip archive
+func (r LocalFileReference) Zip() ([]byte, error) {
 	// Create the ZIP and put the contents in there
 	buffer := new(bytes.Buffer)
 	zipArchive := zip.NewWriter(buffer)
@@ -719,12 +1043,88 @@ func (r LocalFileReference) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
-	bufferData, err := ioutil.ReadAll(buffer)
+	return buffer.Bytes(), nil
+}
+
+// FromZip adds the files in the zip archive data, directories in the archive are skipped
+func (r *LocalFileReference) FromZip(data []byte) error {
+	zipArchive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	// Base64 encode the zipped contents
-	result := base64.StdEncoding.EncodeToString(bufferData)
-	return json.Marshal(result)
+	// The size of the unpacked files is limited in total, the sizes in the archive itself can not be trusted
+	remaining := MaxUnzippedSize
+	for _, file := range zipArchive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		contents, err := readAll(f, remaining)
+		f.Close()
+		if errors.Is(err, ErrFileTooLarge) {
+			return fmt.Errorf("unpacked files exceed %d bytes; %w", MaxUnzippedSize, ErrFileTooLarge)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		remaining -= int64(len(contents))
+
+		r.files = append(r.files, localFileData{
+			filename: file.Name,
+			contents: contents,
+		})
+	}
+
+	return nil
+}
+
+// Names returns the names of the files, in the order they were added
+func (r LocalFileReference) Names() []string {
+	names := make([]string, len(r.files))
+	for k, file := range r.files {
+		names[k] = file.filename
+	}
+
+	return names
+}
+
+// Contents returns the contents of the file with the given name
+func (r LocalFileReference) Contents(filename string) ([]byte, bool) {
+	for _, file := range r.files {
+		if file.filename == filename {
+			return file.contents, true
+		}
+	}
+
+	return nil, false
+}
+
+// Extract writes the files to dir, which is created if it does not exist. Directories in the names of the files are
+// created as well, names that would end up outside of dir are rejected.
+func (r LocalFileReference) Extract(dir string) error {
+	for _, file := range r.files {
+		name := filepath.Clean(filepath.FromSlash(file.filename))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid file name %v", file.filename)
+		}
+
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("could not create directory; %w", err)
+		}
+
+		if err := ioutil.WriteFile(path, file.contents, 0644); err != nil {
+			return fmt.Errorf("could not write %v; %w", file.filename, err)
+		}
+	}
+
+	return nil
 }
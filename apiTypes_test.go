@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -22,17 +23,23 @@ var (
 	_ ApiType = Language{}
 	_ ApiType = Scoreboard{}
 	_ ApiType = State{}
+	_ ApiType = Run{}
+	_ ApiType = Award{}
+	_ ApiType = Commentary{}
 
 	_ Submittable = Clarification{}
+	_ Submittable = Commentary{}
 
 	_ json.Marshaler   = new(ApiTime)
 	_ json.Unmarshaler = new(ApiTime)
 	_ fmt.Stringer     = new(ApiTime)
 
+	_ json.Marshaler   = new(ApiRelTime)
 	_ json.Unmarshaler = new(ApiRelTime)
 	_ fmt.Stringer     = new(ApiRelTime)
 
-	_ json.Marshaler = new(LocalFileReference)
+	_ json.Marshaler   = new(LocalFileReference)
+	_ json.Unmarshaler = new(LocalFileReference)
 )
 
 func TestApiTime_UnmarshalJSON(t *testing.T) {
@@ -95,6 +102,15 @@ func TestApiRelTime_UnmarshalJSON(t *testing.T) {
 	assert.Nil(t, json.Unmarshal([]byte(jsonString), &ti))
 	assert.EqualValues(t, time.Duration(0), ti.T.Duration())
 
+	// Test that the sign applies to the whole time
+	jsonString = `{"T": "-0:03:38.749"}`
+	assert.Nil(t, json.Unmarshal([]byte(jsonString), &ti))
+	assert.EqualValues(t, -duration, ti.T.Duration())
+
+	// Test invalid values
+	jsonString = `{"T": 1234}`
+	assert.NotNil(t, json.Unmarshal([]byte(jsonString), &ti))
+
 	// Test marshalling of an empty type
 	t.Run("marshal-empty", func(t *testing.T) {
 		var rt ApiTime
@@ -104,6 +120,25 @@ func TestApiRelTime_UnmarshalJSON(t *testing.T) {
 	})
 }
 
+func TestApiRelTime_MarshalJSON(t *testing.T) {
+	values := map[time.Duration]string{
+		0: `"0:00:00.000"`,
+		time.Hour*5 + time.Minute*3 + time.Second*38 + time.Millisecond*749: `"5:03:38.749"`,
+		-(time.Minute*10 + time.Millisecond*5):                              `"-0:10:00.005"`,
+	}
+
+	for d, expected := range values {
+		bts, err := json.Marshal(ApiRelTime(d))
+		assert.Nil(t, err)
+		assert.EqualValues(t, expected, bts)
+
+		// Unmarshalling should result in the same time
+		var rt ApiRelTime
+		assert.Nil(t, json.Unmarshal(bts, &rt))
+		assert.EqualValues(t, d, rt.Duration())
+	}
+}
+
 func TestLocalFileReference_MarshalJSON(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		fr := new(LocalFileReference)
@@ -183,3 +218,93 @@ func TestLocalFileReference_MarshalJSON(t *testing.T) {
 		assert.EqualValues(t, goModContents, fileContent)
 	})
 }
+
+func TestLocalFileReference_UnmarshalJSON(t *testing.T) {
+	fr := new(LocalFileReference)
+	assert.Nil(t, fr.FromString("sample.txt", "This is a sample"))
+	assert.Nil(t, fr.FromString("main.cpp", "int main() { return 0; }"))
+
+	data, err := json.Marshal(fr)
+	assert.Nil(t, err)
+
+	var decoded LocalFileReference
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.EqualValues(t, *fr, decoded)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"not base64"`), &decoded))
+	assert.NotNil(t, json.Unmarshal([]byte(`"aGVsbG8="`), &decoded))
+}
+
+func TestLocalFileReference_Extract(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extract")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	fr := new(LocalFileReference)
+	assert.Nil(t, fr.FromString("main.cpp", "int main() { return 0; }"))
+	assert.Nil(t, fr.FromString("lib/util.h", "#pragma once"))
+	assert.EqualValues(t, []string{"main.cpp", "lib/util.h"}, fr.Names())
+
+	contents, ok := fr.Contents("lib/util.h")
+	assert.True(t, ok)
+	assert.EqualValues(t, "#pragma once", string(contents))
+
+	_, ok = fr.Contents("missing.cpp")
+	assert.False(t, ok)
+
+	assert.Nil(t, fr.Extract(dir))
+	contents, err = ioutil.ReadFile(filepath.Join(dir, "lib", "util.h"))
+	assert.Nil(t, err)
+	assert.EqualValues(t, "#pragma once", string(contents))
+
+	// Files must not be written outside of the directory
+	evil := new(LocalFileReference)
+	assert.Nil(t, evil.FromString("../evil.sh", "rm -rf /"))
+	assert.NotNil(t, evil.Extract(dir))
+	_, err = os.Stat(filepath.Join(dir, "..", "evil.sh"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileReference_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(FileReference{Href: "logo.png", Mime: "image/png", Width: 64, Height: 64})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"href": "logo.png", "mime": "image/png", "width": 64, "height": 64}`, string(data))
+
+	var files LocalFileReference
+	assert.Nil(t, files.FromString("main.cpp", "int main() { return 0; }"))
+	data, err = json.Marshal(FileReference{Mime: "application/zip", Data: files})
+	assert.Nil(t, err)
+
+	var decoded FileReference
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.EqualValues(t, files, decoded.Data)
+}
+
+func TestBestFile(t *testing.T) {
+	small := FileReference{Href: "small", Width: 64, Height: 64}
+	medium := FileReference{Href: "medium", Width: 256, Height: 128}
+	large := FileReference{Href: "large", Width: 512, Height: 512}
+	unknown := FileReference{Href: "unknown"}
+
+	_, ok := BestFile(nil, 64, 64)
+	assert.False(t, ok)
+
+	tests := []struct {
+		refs          []FileReference
+		width, height int
+		expected      FileReference
+	}{
+		{[]FileReference{small, medium, large}, 64, 64, small},
+		{[]FileReference{large, medium, small}, 100, 100, medium},
+		{[]FileReference{small, medium, large}, 200, 200, large},
+		{[]FileReference{small, medium}, 1024, 1024, medium},
+		{[]FileReference{unknown, small}, 32, 32, small},
+		{[]FileReference{unknown}, 32, 32, unknown},
+	}
+
+	for _, test := range tests {
+		best, ok := BestFile(test.refs, test.width, test.height)
+		assert.True(t, ok)
+		assert.EqualValues(t, test.expected, best, "%dx%d", test.width, test.height)
+	}
+}
@@ -0,0 +1,157 @@
+package interactor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Versions of the CCS specification. Decoding does not depend on the version a server reports in its ApiInfo: the
+// format of every field that differs between these versions, such as a penalty time in minutes or as a RELTIME, is
+// detected from the value of the field itself.
+const (
+	ApiVersion2020_03 = "2020-03"
+	ApiVersion2021_11 = "2021-11"
+	ApiVersion2022_07 = "2022-07"
+	ApiVersion2023_06 = "2023-06"
+)
+
+type (
+	// ApiInfo describes the API, as served at its root
+	ApiInfo struct {
+		Version    string       `json:"version"`
+		VersionURL string       `json:"version_url"`
+		Name       string       `json:"name,omitempty"`
+		Provider   *ApiProvider `json:"provider,omitempty"`
+	}
+
+	// ApiProvider describes the system providing the API, such as DOMjudge, the CDS or PC^2
+	ApiProvider struct {
+		Name      string `json:"name"`
+		Version   string `json:"version,omitempty"`
+		BuildDate string `json:"build_date,omitempty"`
+	}
+
+	// infoCache keeps the ApiInfo of an interactor, which does not change while the server is running
+	infoCache struct {
+		mu   sync.Mutex
+		info *ApiInfo
+	}
+)
+
+// -- ApiInfo implementation
+
+func (a ApiInfo) FromJSON(data []byte) (ApiType, error) {
+	err := json.Unmarshal(data, &a)
+	return a, err
+}
+
+func (a *ApiInfo) UnmarshalJSON(data []byte) error {
+	type plain ApiInfo
+	var v struct {
+		plain
+		// DOMjudge describes itself in a separate object instead of a provider
+		DOMjudge *struct {
+			Version string `json:"version"`
+		} `json:"domjudge"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*a = ApiInfo(v.plain)
+	if a.Provider == nil && v.DOMjudge != nil {
+		a.Provider = &ApiProvider{Name: "DOMjudge", Version: v.DOMjudge.Version}
+	}
+
+	// Versions before 2021-11 do not report their version
+	if a.Version == "" {
+		a.Version = ApiVersion2020_03
+	}
+
+	return nil
+}
+
+func (a ApiInfo) String() string {
+	var provider string
+	if a.Provider != nil {
+		provider = a.Provider.Name + " " + a.Provider.Version
+	}
+
+	return fmt.Sprintf(`
+    version: %v
+version url: %v
+       name: %v
+   provider: %v
+`, a.Version, a.VersionURL, a.Name, provider)
+}
+
+func (a ApiInfo) InContest() bool {
+	return false
+}
+
+func (a ApiInfo) Path() string {
+	return ""
+}
+
+func (a ApiInfo) Generate() ApiType {
+	return ApiInfo{}
+}
+
+// AtLeast returns whether the API implements version, or a later version of the specification
+func (a ApiInfo) AtLeast(version string) bool {
+	// Versions are dates, which sort lexicographically
+	return a.Version >= version
+}
+
+// decodeMinutes decodes a number of minutes, which is an integer before 2023-06 and a RELTIME since
+func decodeMinutes(data json.RawMessage) (int, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return 0, nil
+	}
+
+	if data[0] != '"' {
+		var minutes int
+		err := json.Unmarshal(data, &minutes)
+		return minutes, err
+	}
+
+	var rel ApiRelTime
+	if err := json.Unmarshal(data, &rel); err != nil {
+		return 0, err
+	}
+
+	return int(rel.Duration() / time.Minute), nil
+}
+
+// decodeIdentifiers decodes a list of identifiers, which some providers give as a single identifier instead
+func decodeIdentifiers(data json.RawMessage) ([]string, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	if data[0] != '[' {
+		var id Identifier
+		if err := json.Unmarshal(data, &id); err != nil {
+			return nil, err
+		}
+
+		return []string{string(id)}, nil
+	}
+
+	var ids []Identifier
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, len(ids))
+	for k, id := range ids {
+		ret[k] = string(id)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,138 @@
+package interactor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiInfo_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		version  string
+		provider *ApiProvider
+	}{
+		{"2023-06", `{"version":"2023-06","version_url":"https://ccs-specs.icpc.io/2023-06/contest_api","provider":{"name":"CDS","version":"2.1"}}`, ApiVersion2023_06, &ApiProvider{Name: "CDS", Version: "2.1"}},
+		{"domjudge", `{"version":"2022-07","version_url":"https://ccs-specs.icpc.io/2022-07/contest_api","domjudge":{"apiversion":4,"version":"8.1.0"}}`, ApiVersion2022_07, &ApiProvider{Name: "DOMjudge", Version: "8.1.0"}},
+		{"unversioned", `{"api_version":4,"domjudge_version":"7.3.0"}`, ApiVersion2020_03, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var info ApiInfo
+			assert.Nil(t, json.Unmarshal([]byte(test.data), &info))
+			assert.EqualValues(t, test.version, info.Version)
+			assert.EqualValues(t, test.provider, info.Provider)
+		})
+	}
+
+	info := ApiInfo{Version: ApiVersion2022_07}
+	assert.True(t, info.AtLeast(ApiVersion2021_11))
+	assert.True(t, info.AtLeast(ApiVersion2022_07))
+	assert.False(t, info.AtLeast(ApiVersion2023_06))
+}
+
+func TestVersionTolerantDecoding(t *testing.T) {
+	t.Run("penalty-time", func(t *testing.T) {
+		for data, expected := range map[string]int{
+			`{"id":"c","penalty_time":20}`:          20,
+			`{"id":"c","penalty_time":"0:15:00"}`:   15,
+			`{"id":"c","penalty_time":"0:20:30.5"}`: 20,
+			`{"id":"c","penalty_time":0}`:           0,
+		} {
+			obj, err := Contest{}.FromJSON([]byte(data))
+			assert.Nil(t, err)
+			if assert.NotNil(t, obj.(Contest).PenaltyTime, data) {
+				assert.EqualValues(t, expected, *obj.(Contest).PenaltyTime, data)
+			}
+		}
+
+		obj, err := Contest{}.FromJSON([]byte(`{"id":"c"}`))
+		assert.Nil(t, err)
+		assert.Nil(t, obj.(Contest).PenaltyTime)
+
+		_, err = Contest{}.FromJSON([]byte(`{"penalty_time":"twenty"}`))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("scoreboard-times", func(t *testing.T) {
+		obj, err := Scoreboard{}.FromJSON([]byte(`{"rows":[{"rank":1,"team_id":"1","score":{"num_solved":1,"total_time":"1:05:00"},"problems":[{"problem_id":"a","solved":true,"time":"0:45:00"}]}]}`))
+		assert.Nil(t, err)
+
+		row := obj.(Scoreboard).Rows[0]
+		assert.EqualValues(t, 65, row.Score.TotalTime)
+		assert.EqualValues(t, 45, row.Problems[0].Time)
+
+		obj, err = Scoreboard{}.FromJSON([]byte(`{"rows":[{"rank":1,"team_id":1,"score":{"num_solved":1,"total_time":65},"problems":[{"problem_id":"a","solved":true,"time":45}]}]}`))
+		assert.Nil(t, err)
+		assert.EqualValues(t, row, obj.(Scoreboard).Rows[0])
+	})
+
+	t.Run("identifier", func(t *testing.T) {
+		var ids []Identifier
+		assert.Nil(t, json.Unmarshal([]byte(`["a\"b", 12, null]`), &ids))
+		assert.EqualValues(t, []Identifier{`a"b`, "12", ""}, ids)
+	})
+
+	t.Run("group-ids", func(t *testing.T) {
+		for data, expected := range map[string][]string{
+			`{"id":"t","group_ids":["g1",2]}`: {"g1", "2"},
+			`{"id":"t","group_ids":"g1"}`:     {"g1"},
+			`{"id":"t","group_id":3}`:         {"3"},
+			`{"id":"t","group_ids":[]}`:       {},
+			`{"id":"t","group_ids":null}`:     nil,
+		} {
+			obj, err := Team{}.FromJSON([]byte(data))
+			assert.Nil(t, err)
+			assert.EqualValues(t, expected, obj.(Team).GroupIds, data)
+		}
+	})
+
+	t.Run("files", func(t *testing.T) {
+		logo := FileReferences{{Href: "logo.png", Mime: "image/png"}}
+		for _, data := range []string{
+			`{"id":"o","logo":[{"href":"logo.png","mime":"image/png"}]}`,
+			`{"id":"o","logo":{"href":"logo.png","mime":"image/png"}}`,
+		} {
+			obj, err := Organization{}.FromJSON([]byte(data))
+			assert.Nil(t, err)
+			assert.EqualValues(t, logo, obj.(Organization).Logo, data)
+		}
+
+		obj, err := Organization{}.FromJSON([]byte(`{"id":"o","logo":null}`))
+		assert.Nil(t, err)
+		assert.Nil(t, obj.(Organization).Logo)
+	})
+}
+
+func TestInfo(t *testing.T) {
+	mock := NewMockServer(DefaultMockData())
+	defer mock.Close()
+
+	// Count the requests for the root, which should only be made once
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			requests++
+		}
+
+		mock.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	api, err := ContestsInteractor(server.URL, "", "", false)
+	assert.Nil(t, err)
+
+	for k := 0; k < 2; k++ {
+		info, err := api.Info()
+		assert.Nil(t, err)
+		assert.EqualValues(t, ApiVersion2022_07, info.Version)
+		assert.EqualValues(t, "api-interactor", info.Provider.Name)
+	}
+
+	assert.EqualValues(t, 1, requests)
+}
@@ -0,0 +1,212 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type (
+	// Authenticator adds credentials to outgoing requests
+	Authenticator interface {
+		Authenticate(r *http.Request) error
+	}
+
+	// Reauthenticator is an Authenticator that can obtain new credentials. When a request is rejected as unauthorized,
+	// Reauthenticate is called and the request is retried once. Requests for reauthentication should be made using
+	// transport, which does not add any credentials.
+	Reauthenticator interface {
+		Authenticator
+		Reauthenticate(ctx context.Context, transport http.RoundTripper) error
+	}
+
+	// BasicAuth authenticates using HTTP basic-auth, no credentials are added when Username is empty
+	BasicAuth struct {
+		Username, Password string
+	}
+
+	// BearerToken authenticates using a bearer token in the Authorization header
+	BearerToken struct {
+		Token string
+	}
+
+	// CookieAuth authenticates using the cookies in Jar, which is updated with the cookies set by the server
+	CookieAuth struct {
+		Jar http.CookieJar
+	}
+
+	// SessionAuth authenticates using a session cookie, which is obtained by posting the credentials as a form to
+	// LoginURL. Logging in happens on the first request that is rejected as unauthorized.
+	SessionAuth struct {
+		CookieAuth
+
+		LoginURL      string
+		Username      string
+		Password      string
+		UsernameField string
+		PasswordField string
+
+		mu sync.Mutex
+	}
+
+	// cookieStorer is implemented by authenticators that keep track of cookies set by the server
+	cookieStorer interface {
+		storeCookies(resp *http.Response)
+	}
+
+	// authTransport is a http.RoundTripper that authenticates all requests to the API at base and reauthenticates
+	// when needed. Requests to other hosts, such as redirects or files hosted elsewhere, are sent unauthenticated.
+	authTransport struct {
+		auth Authenticator
+		base *url.URL
+
+		T http.RoundTripper
+	}
+)
+
+func (b BasicAuth) Authenticate(r *http.Request) error {
+	if b.Username != "" {
+		r.SetBasicAuth(b.Username, b.Password)
+	}
+
+	return nil
+}
+
+func (b BearerToken) Authenticate(r *http.Request) error {
+	r.Header.Set("Authorization", "Bearer "+b.Token)
+	return nil
+}
+
+// NewCookieAuth constructs a CookieAuth with an empty cookie jar
+func NewCookieAuth() (*CookieAuth, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CookieAuth{Jar: jar}, nil
+}
+
+func (c *CookieAuth) Authenticate(r *http.Request) error {
+	for _, cookie := range c.Jar.Cookies(r.URL) {
+		r.AddCookie(cookie)
+	}
+
+	return nil
+}
+
+func (c *CookieAuth) storeCookies(resp *http.Response) {
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		c.Jar.SetCookies(resp.Request.URL, cookies)
+	}
+}
+
+// NewSessionAuth constructs a SessionAuth, logging in at loginUrl with the form fields "username" and "password"
+func NewSessionAuth(loginUrl, username, password string) (*SessionAuth, error) {
+	c, err := NewCookieAuth()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionAuth{
+		CookieAuth:    *c,
+		LoginURL:      loginUrl,
+		Username:      username,
+		Password:      password,
+		UsernameField: "username",
+		PasswordField: "password",
+	}, nil
+}
+
+// Reauthenticate logs in at LoginURL and stores the resulting session cookie
+func (s *SessionAuth) Reauthenticate(ctx context.Context, transport http.RoundTripper) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	form := url.Values{}
+	form.Set(s.UsernameField, s.Username)
+	form.Set(s.PasswordField, s.Password)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.LoginURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// A successful login is often followed by a redirect, which does not have to be followed
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("login failed with statuscode %d", resp.StatusCode)
+	}
+
+	s.storeCookies(resp)
+	return nil
+}
+
+// RoundTrip authenticates the request, when the request is rejected as unauthorized and the Authenticator is a
+// Reauthenticator the request is authenticated again and retried once
+func (t authTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if t.auth == nil || !t.toBase(request.URL) {
+		return t.T.RoundTrip(request)
+	}
+
+	resp, err := t.roundTrip(request)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	re, ok := t.auth.(Reauthenticator)
+	if !ok || (request.Body != nil && request.Body != http.NoBody && request.GetBody == nil) {
+		// The request can not be retried
+		return resp, nil
+	}
+
+	resp.Body.Close()
+	if err := re.Reauthenticate(request.Context(), t.T); err != nil {
+		return nil, fmt.Errorf("could not reauthenticate; %w", err)
+	}
+
+	// Rewind the body before retrying
+	retry := request.Clone(request.Context())
+	if request.GetBody != nil {
+		if retry.Body, err = request.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.roundTrip(retry)
+}
+
+func (t authTransport) roundTrip(request *http.Request) (*http.Response, error) {
+	// A RoundTripper should not modify the request, authenticate a copy
+	r := request.Clone(request.Context())
+	if err := t.auth.Authenticate(r); err != nil {
+		return nil, fmt.Errorf("could not authenticate request; %w", err)
+	}
+
+	resp, err := t.T.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := t.auth.(cookieStorer); ok {
+		s.storeCookies(resp)
+	}
+
+	return resp, nil
+}
+
+// toBase returns whether u refers to the host of the API, the credentials are never sent to any other host
+func (t authTransport) toBase(u *url.URL) bool {
+	return t.base != nil && strings.EqualFold(u.Scheme, t.base.Scheme) && strings.EqualFold(u.Host, t.base.Host)
+}
@@ -0,0 +1,158 @@
+package interactor
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBearerToken(t *testing.T) {
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+	}))
+	defer ser.Close()
+
+	api, err := NewContestInteractor(ser.URL, "test", WithBearerToken("secret"))
+	assert.Nil(t, err)
+	assert.NotNil(t, api)
+
+	api, err = NewContestInteractor(ser.URL, "test", WithBearerToken("wrong"))
+	assert.NotNil(t, err)
+	assert.Nil(t, api)
+}
+
+func TestAuthOtherHost(t *testing.T) {
+	// other is a different host, which serves files but must never receive the credentials
+	var leaked, requests int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("Authorization") != "" {
+			atomic.AddInt32(&leaked, 1)
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("logo"))
+	}))
+	defer other.Close()
+
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/logo":
+			http.Redirect(w, r, other.URL+"/logo.png", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ser.Close()
+
+	api, err := NewContestInteractor(ser.URL, "test", WithBearerToken("secret"))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	for _, href := range []string{"contests/test/logo", other.URL + "/logo.png"} {
+		data, err := api.Download(FileReference{Href: href})
+		assert.Nil(t, err, href)
+		assert.EqualValues(t, "logo", string(data), href)
+	}
+
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+	assert.EqualValues(t, 0, atomic.LoadInt32(&leaked))
+}
+
+func TestBasicAuth(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, BasicAuth{Username: "team"}.Authenticate(r))
+
+	user, pass, ok := r.BasicAuth()
+	assert.True(t, ok)
+	assert.EqualValues(t, "team", user)
+	assert.EqualValues(t, "", pass)
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.Nil(t, BasicAuth{}.Authenticate(r))
+	_, _, ok = r.BasicAuth()
+	assert.False(t, ok)
+}
+
+func TestSessionAuth(t *testing.T) {
+	var (
+		logins  int32
+		session = "first"
+	)
+
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			assert.Nil(t, r.ParseForm())
+			if r.PostForm.Get("username") != "admin" || r.PostForm.Get("password") != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			atomic.AddInt32(&logins, 1)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: session, Path: "/"})
+			w.WriteHeader(http.StatusFound)
+			return
+		}
+
+		if c, err := r.Cookie("session"); err != nil || c.Value != session {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/clarifications":
+			clar, err := Clarification{}.FromJSON(mustReadAll(t, r))
+			assert.Nil(t, err)
+			_, _ = w.Write([]byte(`{"id":"c1","text":"` + clar.(Clarification).Text + `"}`))
+		}
+	}))
+	defer ser.Close()
+
+	auth, err := NewSessionAuth(ser.URL+"/login", "admin", "secret")
+	assert.Nil(t, err)
+
+	api, err := NewContestInteractor(ser.URL, "test", WithAuthenticator(auth))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&logins))
+
+	// Expire the session, the post should be retried including its body after logging in again
+	session = "second"
+	clar, err := api.PostClarification("A", "retried")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "retried", clar.Text)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&logins))
+
+	t.Run("wrong-credentials", func(t *testing.T) {
+		auth, err := NewSessionAuth(ser.URL+"/login", "admin", "wrong")
+		assert.Nil(t, err)
+
+		api, err := NewContestInteractor(ser.URL, "test", WithAuthenticator(auth))
+		assert.NotNil(t, err)
+		assert.Nil(t, api)
+	})
+}
+
+func mustReadAll(t *testing.T, r *http.Request) []byte {
+	bts, err := ioutil.ReadAll(r.Body)
+	assert.Nil(t, err)
+	return bts
+}
@@ -0,0 +1,160 @@
+package interactor
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AwardOptions determine which awards are computed by ComputeAwards
+type AwardOptions struct {
+	// Gold, Silver and Bronze are the number of medals of each kind. Medals are awarded on rank, such that teams that
+	// share a rank receive the same medal.
+	Gold   int
+	Silver int
+	Bronze int
+	// GroupWinners enables the awards for the best team of every group that is not hidden
+	GroupWinners bool
+	// FirstToSolve enables the awards for the first team to solve each problem
+	FirstToSolve bool
+}
+
+// ComputeAwards derives awards from the scoreboard, using input for the groups of teams and the submissions used to
+// determine who solved a problem first. Only teams on the scoreboard that solved at least one problem are awarded,
+// awards that no team qualifies for are left out. The ids of the awards follow the conventions of the CCS
+// specification, such as "winner", "gold-medal", "group-winner-<group id>" and "first-to-solve-<problem id>".
+func ComputeAwards(scoreboard Scoreboard, input ScoreboardInput, opts AwardOptions) []Award {
+	var awards []Award
+	add := func(id, citation string, teamIds []string) {
+		if len(teamIds) > 0 {
+			awards = append(awards, Award{Id: id, Citation: citation, TeamIds: teamIds})
+		}
+	}
+
+	var rows []Row
+	for _, row := range scoreboard.Rows {
+		if row.Score.NumSolved > 0 {
+			rows = append(rows, row)
+		}
+	}
+	sort.SliceStable(rows, func(a, b int) bool { return rows[a].Rank < rows[b].Rank })
+
+	teamsByRank := func(from, to int) []string {
+		var teamIds []string
+		for _, row := range rows {
+			if row.Rank >= from && row.Rank <= to {
+				teamIds = append(teamIds, string(row.TeamId))
+			}
+		}
+
+		return teamIds
+	}
+
+	add("winner", "Contest winner", teamsByRank(1, 1))
+	add("gold-medal", "Gold medal winner", teamsByRank(1, opts.Gold))
+	add("silver-medal", "Silver medal winner", teamsByRank(opts.Gold+1, opts.Gold+opts.Silver))
+	add("bronze-medal", "Bronze medal winner", teamsByRank(opts.Gold+opts.Silver+1, opts.Gold+opts.Silver+opts.Bronze))
+
+	if opts.GroupWinners {
+		teams := make(map[string]Team, len(input.Teams))
+		for _, team := range input.Teams {
+			teams[team.Id] = team
+		}
+
+		groups := make([]Group, len(input.Groups))
+		copy(groups, input.Groups)
+		sort.SliceStable(groups, func(a, b int) bool { return groups[a].Id < groups[b].Id })
+
+		for _, group := range groups {
+			if group.Hidden {
+				continue
+			}
+
+			// The rows are ordered by rank, so the first team of the group determines the winning rank
+			var teamIds []string
+			rank := 0
+			for _, row := range rows {
+				if !containsString(teams[string(row.TeamId)].GroupIds, group.Id) || (rank > 0 && row.Rank != rank) {
+					continue
+				}
+
+				rank = row.Rank
+				teamIds = append(teamIds, string(row.TeamId))
+			}
+
+			add("group-winner-"+group.Id, fmt.Sprintf("Winner(s) of group %s", group.Name), teamIds)
+		}
+	}
+
+	if opts.FirstToSolve {
+		for _, p := range firstToSolve(rows, input) {
+			add("first-to-solve-"+p.problem.Id, fmt.Sprintf("First to solve problem %s", p.problem.Label), p.teamIds)
+		}
+	}
+
+	return awards
+}
+
+type firstSolve struct {
+	problem Problem
+	teamIds []string
+}
+
+// firstToSolve returns for every problem, ordered by ordinal, the teams that solved it first. Teams that submitted a
+// correct solution at the same contest time are all considered first.
+func firstToSolve(rows []Row, input ScoreboardInput) []firstSolve {
+	ranked := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		ranked[string(row.TeamId)] = true
+	}
+
+	solved := make(map[string]bool, len(input.JudgementTypes))
+	for _, jt := range input.JudgementTypes {
+		solved[jt.Id] = jt.Solved
+	}
+
+	judgements := currentJudgements(input.Judgements)
+	first := make(map[string][]Submission)
+	for _, s := range input.Submissions {
+		if !ranked[s.TeamId] || !solved[judgements[s.Id]] || s.ContestTime < 0 ||
+			(input.Contest.Duration > 0 && s.ContestTime >= input.Contest.Duration) {
+			continue
+		}
+
+		current := first[s.ProblemId]
+		switch {
+		case len(current) == 0 || s.ContestTime < current[0].ContestTime:
+			first[s.ProblemId] = []Submission{s}
+		case s.ContestTime == current[0].ContestTime:
+			first[s.ProblemId] = append(current, s)
+		}
+	}
+
+	problems := make([]Problem, len(input.Problems))
+	copy(problems, input.Problems)
+	sort.SliceStable(problems, func(a, b int) bool { return problems[a].Ordinal < problems[b].Ordinal })
+
+	ret := make([]firstSolve, 0, len(problems))
+	for _, p := range problems {
+		var teamIds []string
+		for _, s := range first[p.Id] {
+			if !containsString(teamIds, s.TeamId) {
+				teamIds = append(teamIds, s.TeamId)
+			}
+		}
+
+		sort.Strings(teamIds)
+		ret = append(ret, firstSolve{problem: p, teamIds: teamIds})
+	}
+
+	return ret
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
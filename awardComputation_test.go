@@ -0,0 +1,55 @@
+package interactor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeAwards(t *testing.T) {
+	c := DefaultMockData().Contests[0]
+	input := ScoreboardInput{
+		Contest:        c.Contest,
+		Problems:       c.Problems,
+		Groups:         c.Groups,
+		Teams:          c.Teams,
+		Submissions:    c.Submissions,
+		Judgements:     c.Judgements,
+		JudgementTypes: c.JudgementTypes,
+	}
+	scoreboard := ComputeScoreboard(input, ScoreboardOptions{})
+
+	t.Run("all", func(t *testing.T) {
+		awards := ComputeAwards(scoreboard, input, AwardOptions{Gold: 1, Silver: 1, Bronze: 1, GroupWinners: true, FirstToSolve: true})
+		assert.EqualValues(t, []Award{
+			{Id: "winner", Citation: "Contest winner", TeamIds: []string{"2"}},
+			{Id: "gold-medal", Citation: "Gold medal winner", TeamIds: []string{"2"}},
+			{Id: "silver-medal", Citation: "Silver medal winner", TeamIds: []string{"1"}},
+			{Id: "group-winner-participants", Citation: "Winner(s) of group Participants", TeamIds: []string{"2"}},
+			{Id: "first-to-solve-accesspoints", Citation: "First to solve problem A", TeamIds: []string{"1"}},
+			{Id: "first-to-solve-brexit", Citation: "First to solve problem B", TeamIds: []string{"2"}},
+		}, awards)
+	})
+
+	t.Run("ties", func(t *testing.T) {
+		// Both teams solve the same problem at the same time, they share all awards
+		tied := input
+		tied.Submissions = []Submission{
+			{Id: "s1", TeamId: "1", ProblemId: "circuitdesign", ContestTime: ApiRelTime(60)},
+			{Id: "s2", TeamId: "2", ProblemId: "circuitdesign", ContestTime: ApiRelTime(60)},
+		}
+		tied.Judgements = []Judgement{
+			{Id: "j1", SubmissionId: "s1", JudgementTypeId: "AC", EndTime: new(ApiTime)},
+			{Id: "j2", SubmissionId: "s2", JudgementTypeId: "AC", EndTime: new(ApiTime)},
+		}
+
+		awards := ComputeAwards(ComputeScoreboard(tied, ScoreboardOptions{}), tied, AwardOptions{Gold: 1, Silver: 1, FirstToSolve: true})
+		if assert.Len(t, awards, 3) {
+			assert.EqualValues(t, []string{"1", "2"}, awards[0].TeamIds)
+			assert.EqualValues(t, "gold-medal", awards[1].Id)
+			assert.EqualValues(t, []string{"1", "2"}, awards[1].TeamIds)
+			assert.EqualValues(t, "first-to-solve-circuitdesign", awards[2].Id)
+			assert.EqualValues(t, []string{"1", "2"}, awards[2].TeamIds)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package interactor
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// ResponseCache caches decoded responses of GET requests, keyed by the path relative to the base url. Cached
+	// responses are revalidated with the server using If-None-Match and If-Modified-Since, when the server responds
+	// with 304 Not Modified the cached objects are returned. Only responses with an ETag or Last-Modified header are
+	// cached. A ResponseCache is safe for concurrent use, but should only be shared by interactors for the same server
+	// and credentials.
+	ResponseCache struct {
+		ttl        time.Duration
+		maxEntries int
+
+		mu      sync.Mutex
+		entries map[string]*list.Element
+		lru     *list.List
+	}
+
+	cacheEntry struct {
+		path         string
+		etag         string
+		lastModified string
+		objs         []ApiType
+		stored       time.Time
+	}
+)
+
+// NewResponseCache constructs a ResponseCache. Entries older than ttl are discarded, 0 for no limit. When more than
+// maxEntries responses are cached the least recently used one is discarded, 0 for no limit.
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	return &ResponseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Invalidate discards the cached response for path, as well as all responses for paths below it. For example
+// invalidating "contests/wf/teams" discards both the list of teams and all single teams.
+func (c *ResponseCache) Invalidate(path string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path = strings.Trim(path, "/")
+	for key, el := range c.entries {
+		if key == path || strings.HasPrefix(key, path+"/") {
+			c.remove(el)
+		}
+	}
+}
+
+// Clear discards all cached responses
+func (c *ResponseCache) Clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// Len returns the number of cached responses
+func (c *ResponseCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// lookup returns the entry for path, nil if there is none or it expired
+func (c *ResponseCache) lookup(path string) *cacheEntry {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[strings.Trim(path, "/")]
+	if !ok {
+		return nil
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if c.ttl > 0 && time.Since(entry.stored) > c.ttl {
+		c.remove(el)
+		return nil
+	}
+
+	c.lru.MoveToFront(el)
+	return entry
+}
+
+// store caches objs as the response for path, when resp contains validators
+func (c *ResponseCache) store(path string, resp *http.Response, objs []ApiType) {
+	if c == nil {
+		return
+	}
+
+	entry := &cacheEntry{
+		path:         strings.Trim(path, "/"),
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		objs:         objs,
+		stored:       time.Now(),
+	}
+
+	if entry.etag == "" && entry.lastModified == "" {
+		// The response can not be revalidated
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[entry.path]; ok {
+		c.remove(el)
+	}
+
+	c.entries[entry.path] = c.lru.PushFront(entry)
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *ResponseCache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).path)
+}
+
+// header returns the headers to revalidate the entry, nil when there is no entry
+func (e *cacheEntry) header() http.Header {
+	if e == nil {
+		return nil
+	}
+
+	h := make(http.Header)
+	if e.etag != "" {
+		h.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		h.Set("If-Modified-Since", e.lastModified)
+	}
+
+	return h
+}
+
+// objects returns a copy of the cached objects
+func (e *cacheEntry) objects() []ApiType {
+	return append([]ApiType(nil), e.objs...)
+}
@@ -0,0 +1,103 @@
+package interactor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCache(t *testing.T) {
+	var full, notModified int32
+
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/teams", "/contests/test/problems", "/contests/test/groups":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			atomic.AddInt32(&full, 1)
+			_, _ = w.Write([]byte(`[{"id":"1","name":"First"}]`))
+		case "/contests/test/organizations":
+			// No validators, so nothing should be cached
+			_, _ = w.Write([]byte(`[{"id":"1","name":"First"}]`))
+		}
+	}))
+	defer ser.Close()
+
+	reset := func() {
+		atomic.StoreInt32(&full, 0)
+		atomic.StoreInt32(&notModified, 0)
+	}
+
+	cache := NewResponseCache(0, 2)
+	api, err := NewContestInteractor(ser.URL, "test", WithCache(cache))
+	assert.Nil(t, err)
+
+	t.Run("revalidate", func(t *testing.T) {
+		reset()
+		for k := 0; k < 3; k++ {
+			teams, err := api.Teams()
+			assert.Nil(t, err)
+			assert.Len(t, teams, 1)
+			assert.EqualValues(t, "First", teams[0].Name)
+		}
+
+		assert.EqualValues(t, 1, atomic.LoadInt32(&full))
+		assert.EqualValues(t, 2, atomic.LoadInt32(&notModified))
+	})
+
+	t.Run("no-validators", func(t *testing.T) {
+		before := cache.Len()
+		_, err := api.Organizations()
+		assert.Nil(t, err)
+		assert.EqualValues(t, before, cache.Len())
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		reset()
+		cache.Invalidate("contests/test/teams")
+		_, err := api.Teams()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&full))
+	})
+
+	t.Run("max-entries", func(t *testing.T) {
+		_, _ = api.Teams()
+		_, _ = api.Problems()
+		_, _ = api.Groups()
+		assert.EqualValues(t, 2, cache.Len())
+
+		// Teams was the least recently used, so it should have been evicted
+		reset()
+		_, _ = api.Teams()
+		assert.EqualValues(t, 1, atomic.LoadInt32(&full))
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		cache := NewResponseCache(10*time.Millisecond, 0)
+		api, err := NewContestInteractor(ser.URL, "test", WithCache(cache))
+		assert.Nil(t, err)
+
+		reset()
+		_, _ = api.Teams()
+		time.Sleep(20 * time.Millisecond)
+		_, _ = api.Teams()
+		assert.EqualValues(t, 2, atomic.LoadInt32(&full))
+		assert.EqualValues(t, 0, atomic.LoadInt32(&notModified))
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		cache.Clear()
+		assert.EqualValues(t, 0, cache.Len())
+	})
+}
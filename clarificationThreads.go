@@ -0,0 +1,104 @@
+package interactor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ClarificationThread is a clarification together with all replies to it
+type ClarificationThread struct {
+	Clarification Clarification
+	// Replies are ordered by contest time, replies to replies are part of the thread of the original clarification
+	Replies []Clarification
+}
+
+// ClarificationReply constructs a reply to question, which is sent to the team that asked the question or broadcast
+// to all teams when broadcast is true
+func ClarificationReply(question Clarification, text string, broadcast bool) Clarification {
+	reply := Clarification{
+		ReplyToId: question.Id,
+		ProblemId: question.ProblemId,
+		Text:      text,
+	}
+
+	if !broadcast {
+		reply.ToTeamId = question.FromTeamId
+	}
+
+	return reply
+}
+
+// BuildClarificationThreads groups clarifications into threads using ReplyToId, ordered by the contest time of the
+// clarification starting the thread. Replies to clarifications that are not in clarifications start their own thread.
+func BuildClarificationThreads(clarifications []Clarification) []ClarificationThread {
+	byId := make(map[string]Clarification, len(clarifications))
+	for _, c := range clarifications {
+		byId[c.Id] = c
+	}
+
+	// root returns the clarification starting the thread of c, guarding against cycles
+	root := func(c Clarification) string {
+		seen := map[string]bool{c.Id: true}
+		for {
+			parent, ok := byId[c.ReplyToId]
+			if c.ReplyToId == "" || !ok || seen[parent.Id] {
+				return c.Id
+			}
+
+			seen[parent.Id] = true
+			c = parent
+		}
+	}
+
+	var threads []ClarificationThread
+	index := make(map[string]int)
+	for _, c := range clarifications {
+		if root(c) == c.Id {
+			index[c.Id] = len(threads)
+			threads = append(threads, ClarificationThread{Clarification: c})
+		}
+	}
+
+	for _, c := range clarifications {
+		r := root(c)
+		if r == c.Id {
+			continue
+		}
+
+		if k, ok := index[r]; ok {
+			threads[k].Replies = append(threads[k].Replies, c)
+		} else {
+			// The clarifications reply to each other in a cycle
+			index[c.Id] = len(threads)
+			threads = append(threads, ClarificationThread{Clarification: c})
+		}
+	}
+
+	for k := range threads {
+		replies := threads[k].Replies
+		sort.SliceStable(replies, func(a, b int) bool { return replies[a].ContestTime < replies[b].ContestTime })
+	}
+
+	sort.SliceStable(threads, func(a, b int) bool {
+		return threads[a].Clarification.ContestTime < threads[b].Clarification.ContestTime
+	})
+	return threads
+}
+
+// Answered returns whether the thread contains a reply
+func (t ClarificationThread) Answered() bool {
+	return len(t.Replies) > 0
+}
+
+func (t ClarificationThread) String() string {
+	replies := make([]string, len(t.Replies))
+	for k, reply := range t.Replies {
+		replies[k] = reply.String()
+	}
+
+	return fmt.Sprintf(`
+clarification: %v
+      replies: %v
+`, t.Clarification, strings.Join(replies, ""))
+}
@@ -0,0 +1,57 @@
+package interactor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildClarificationThreads(t *testing.T) {
+	clarifications := []Clarification{
+		{Id: "r2", ReplyToId: "q1", Text: "Second reply", ContestTime: 40},
+		{Id: "q1", FromTeamId: "t1", Text: "Question", ContestTime: 10},
+		{Id: "r1", ReplyToId: "q1", ToTeamId: "t1", Text: "Reply", ContestTime: 20},
+		{Id: "r3", ReplyToId: "r1", Text: "Reply to a reply", ContestTime: 50},
+		{Id: "b1", Text: "Broadcast", ContestTime: 30},
+		{Id: "o1", ReplyToId: "unknown", Text: "Orphan", ContestTime: 5},
+		{Id: "x1", ReplyToId: "x2", ContestTime: 60},
+		{Id: "x2", ReplyToId: "x1", ContestTime: 70},
+	}
+
+	threads := BuildClarificationThreads(clarifications)
+	ids := func(clarifications []Clarification) []string {
+		var ids []string
+		for _, c := range clarifications {
+			ids = append(ids, c.Id)
+		}
+
+		return ids
+	}
+
+	if !assert.Len(t, threads, 4) {
+		return
+	}
+
+	assert.EqualValues(t, "o1", threads[0].Clarification.Id)
+	assert.False(t, threads[0].Answered())
+
+	assert.EqualValues(t, "q1", threads[1].Clarification.Id)
+	assert.True(t, threads[1].Answered())
+	assert.EqualValues(t, []string{"r1", "r2", "r3"}, ids(threads[1].Replies))
+
+	assert.EqualValues(t, "b1", threads[2].Clarification.Id)
+
+	// Clarifications replying to each other end up in a single thread
+	assert.EqualValues(t, 1, len(threads[3].Replies))
+}
+
+func TestClarificationReply(t *testing.T) {
+	question := Clarification{Id: "q1", FromTeamId: "t1", ProblemId: "a", Text: "Question"}
+
+	reply := ClarificationReply(question, "Answer", false)
+	assert.EqualValues(t, Clarification{ReplyToId: "q1", ToTeamId: "t1", ProblemId: "a", Text: "Answer"}, reply)
+
+	broadcast := ClarificationReply(question, "Answer", true)
+	assert.Empty(t, broadcast.ToTeamId)
+	assert.EqualValues(t, "q1", broadcast.ReplyToId)
+}
@@ -0,0 +1,657 @@
+package interactor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+type (
+	// EventSource is anything events can be read from, such as an EventReader or a Subscription
+	EventSource interface {
+		Next() (Event, error)
+	}
+
+	// ContestModel is an in-memory representation of a contest, indexed by id. It is safe for concurrent use by
+	// multiple readers while a single writer applies updates.
+	ContestModel struct {
+		mu sync.RWMutex
+
+		contest        Contest
+		state          State
+		judgementTypes map[string]JudgementType
+		languages      map[string]Language
+		problems       map[string]Problem
+		groups         map[string]Group
+		organizations  map[string]Organization
+		teams          map[string]Team
+		persons        map[string]Person
+		submissions    map[string]Submission
+		judgements     map[string]Judgement
+		runs           map[string]Run
+		clarifications map[string]Clarification
+		awards         map[string]Award
+		commentary     map[string]Commentary
+	}
+)
+
+// NewContestModel constructs an empty ContestModel
+func NewContestModel() *ContestModel {
+	return &ContestModel{
+		judgementTypes: make(map[string]JudgementType),
+		languages:      make(map[string]Language),
+		problems:       make(map[string]Problem),
+		groups:         make(map[string]Group),
+		organizations:  make(map[string]Organization),
+		teams:          make(map[string]Team),
+		persons:        make(map[string]Person),
+		submissions:    make(map[string]Submission),
+		judgements:     make(map[string]Judgement),
+		runs:           make(map[string]Run),
+		clarifications: make(map[string]Clarification),
+		awards:         make(map[string]Award),
+		commentary:     make(map[string]Commentary),
+	}
+}
+
+// LoadContestModel constructs a ContestModel from a full snapshot of the given api
+func LoadContestModel(api ContestApi) (*ContestModel, error) {
+	m := NewContestModel()
+	if err := m.Load(api); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Load retrieves all objects from api and adds them to the model. Objects already in the model are kept, unless they
+// are replaced by an object with the same id.
+func (m *ContestModel) Load(api ContestApi) error {
+	var objs []ApiType
+
+	contest, err := api.Contest()
+	if err != nil {
+		return fmt.Errorf("could not load contest; %w", err)
+	}
+	objs = append(objs, contest)
+
+	state, err := api.State()
+	if err != nil {
+		return fmt.Errorf("could not load state; %w", err)
+	}
+	objs = append(objs, state)
+
+	// All lists are retrieved through GetObjects, such that they can be added in bulk
+	for _, typ := range []ApiType{JudgementType{}, Language{}, Problem{}, Group{}, Organization{}, Team{},
+		Submission{}, Judgement{}, Clarification{}} {
+		list, err := api.GetObjects(typ)
+		if err != nil {
+			return fmt.Errorf("could not load %s; %w", typ.Path(), err)
+		}
+
+		objs = append(objs, list...)
+	}
+
+	// Not every CCS provides these endpoints
+	for _, typ := range []ApiType{Person{}, Run{}, Award{}, Commentary{}} {
+		list, err := api.GetObjects(typ)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("could not load %s; %w", typ.Path(), err)
+		}
+
+		objs = append(objs, list...)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, obj := range objs {
+		m.update(obj)
+	}
+
+	return nil
+}
+
+// Consume applies all events read from src, until src is exhausted or an error occurs
+func (m *ContestModel) Consume(src EventSource) error {
+	for {
+		e, err := src.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := m.Apply(e); err != nil {
+			return err
+		}
+	}
+}
+
+// Apply applies a single event to the model. Events of types that are not part of the model are ignored.
+func (m *ContestModel) Apply(e Event) error {
+	typ, ok := eventTypes[e.Type]
+	if !ok {
+		return nil
+	}
+
+	if e.Op == OpDelete {
+		m.Delete(typ, e.ObjectId)
+		return nil
+	}
+
+	if e.Objects != nil {
+		m.Replace(typ, e.Objects)
+		return nil
+	}
+
+	if e.Data == nil {
+		return fmt.Errorf("event for %s is missing data", e.Type)
+	}
+
+	m.Update(e.Data)
+	return nil
+}
+
+// Update creates or replaces obj in the model. Objects of types that are not part of the model are ignored.
+func (m *ContestModel) Update(obj ApiType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.update(obj)
+}
+
+func (m *ContestModel) update(obj ApiType) {
+	switch v := obj.(type) {
+	case Contest:
+		m.contest = v
+	case State:
+		m.state = v
+	case JudgementType:
+		m.judgementTypes[v.Id] = v
+	case Language:
+		m.languages[v.Id] = v
+	case Problem:
+		m.problems[v.Id] = v
+	case Group:
+		m.groups[v.Id] = v
+	case Organization:
+		m.organizations[v.Id] = v
+	case Team:
+		m.teams[v.Id] = v
+	case Person:
+		m.persons[v.Id] = v
+	case Submission:
+		m.submissions[v.Id] = v
+	case Judgement:
+		m.judgements[v.Id] = v
+	case Run:
+		m.runs[v.Id] = v
+	case Clarification:
+		m.clarifications[v.Id] = v
+	case Award:
+		m.awards[v.Id] = v
+	case Commentary:
+		m.commentary[v.Id] = v
+	}
+}
+
+// Replace replaces all objects of the type of typ in the model by objs
+func (m *ContestModel) Replace(typ ApiType, objs []ApiType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch typ.(type) {
+	case Contest:
+		m.contest = Contest{}
+	case State:
+		m.state = State{}
+	case JudgementType:
+		m.judgementTypes = make(map[string]JudgementType)
+	case Language:
+		m.languages = make(map[string]Language)
+	case Problem:
+		m.problems = make(map[string]Problem)
+	case Group:
+		m.groups = make(map[string]Group)
+	case Organization:
+		m.organizations = make(map[string]Organization)
+	case Team:
+		m.teams = make(map[string]Team)
+	case Person:
+		m.persons = make(map[string]Person)
+	case Submission:
+		m.submissions = make(map[string]Submission)
+	case Judgement:
+		m.judgements = make(map[string]Judgement)
+	case Run:
+		m.runs = make(map[string]Run)
+	case Clarification:
+		m.clarifications = make(map[string]Clarification)
+	case Award:
+		m.awards = make(map[string]Award)
+	case Commentary:
+		m.commentary = make(map[string]Commentary)
+	}
+
+	for _, obj := range objs {
+		m.update(obj)
+	}
+}
+
+// Delete removes the object of the type of typ with the given id from the model. Singletons, such as the contest and
+// the state, are reset to their zero value.
+func (m *ContestModel) Delete(typ ApiType, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch typ.(type) {
+	case Contest:
+		m.contest = Contest{}
+	case State:
+		m.state = State{}
+	case JudgementType:
+		delete(m.judgementTypes, id)
+	case Language:
+		delete(m.languages, id)
+	case Problem:
+		delete(m.problems, id)
+	case Group:
+		delete(m.groups, id)
+	case Organization:
+		delete(m.organizations, id)
+	case Team:
+		delete(m.teams, id)
+	case Person:
+		delete(m.persons, id)
+	case Submission:
+		delete(m.submissions, id)
+	case Judgement:
+		delete(m.judgements, id)
+	case Run:
+		delete(m.runs, id)
+	case Clarification:
+		delete(m.clarifications, id)
+	case Award:
+		delete(m.awards, id)
+	case Commentary:
+		delete(m.commentary, id)
+	}
+}
+
+func (m *ContestModel) Contest() Contest {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.contest
+}
+
+func (m *ContestModel) State() State {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.state
+}
+
+// JudgementTypes returns all judgement types, sorted by id
+func (m *ContestModel) JudgementTypes() []JudgementType {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]JudgementType, 0, len(m.judgementTypes))
+	for _, v := range m.judgementTypes {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool { return ret[a].Id < ret[b].Id })
+	return ret
+}
+
+func (m *ContestModel) JudgementTypeById(judgementTypeId string) (JudgementType, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.judgementTypes[judgementTypeId]
+	return v, ok
+}
+
+// Languages returns all languages, sorted by id
+func (m *ContestModel) Languages() []Language {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Language, 0, len(m.languages))
+	for _, v := range m.languages {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool { return ret[a].Id < ret[b].Id })
+	return ret
+}
+
+func (m *ContestModel) LanguageById(languageId string) (Language, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.languages[languageId]
+	return v, ok
+}
+
+// Problems returns all problems, sorted by ordinal
+func (m *ContestModel) Problems() []Problem {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Problem, 0, len(m.problems))
+	for _, v := range m.problems {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool {
+		if ret[a].Ordinal != ret[b].Ordinal {
+			return ret[a].Ordinal < ret[b].Ordinal
+		}
+
+		return ret[a].Id < ret[b].Id
+	})
+	return ret
+}
+
+func (m *ContestModel) ProblemById(problemId string) (Problem, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.problems[problemId]
+	return v, ok
+}
+
+// Groups returns all groups, sorted by id
+func (m *ContestModel) Groups() []Group {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Group, 0, len(m.groups))
+	for _, v := range m.groups {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool { return ret[a].Id < ret[b].Id })
+	return ret
+}
+
+func (m *ContestModel) GroupById(groupId string) (Group, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.groups[groupId]
+	return v, ok
+}
+
+// Organizations returns all organizations, sorted by id
+func (m *ContestModel) Organizations() []Organization {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Organization, 0, len(m.organizations))
+	for _, v := range m.organizations {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool { return ret[a].Id < ret[b].Id })
+	return ret
+}
+
+func (m *ContestModel) OrganizationById(organizationId string) (Organization, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.organizations[organizationId]
+	return v, ok
+}
+
+// Teams returns all teams, sorted by id
+func (m *ContestModel) Teams() []Team {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Team, 0, len(m.teams))
+	for _, v := range m.teams {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool { return ret[a].Id < ret[b].Id })
+	return ret
+}
+
+func (m *ContestModel) TeamById(teamId string) (Team, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.teams[teamId]
+	return v, ok
+}
+
+// Persons returns all persons, sorted by id
+func (m *ContestModel) Persons() []Person {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Person, 0, len(m.persons))
+	for _, v := range m.persons {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool { return ret[a].Id < ret[b].Id })
+	return ret
+}
+
+func (m *ContestModel) PersonById(personId string) (Person, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.persons[personId]
+	return v, ok
+}
+
+// Submissions returns all submissions, sorted by contest time
+func (m *ContestModel) Submissions() []Submission {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Submission, 0, len(m.submissions))
+	for _, v := range m.submissions {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool {
+		if ret[a].ContestTime != ret[b].ContestTime {
+			return ret[a].ContestTime < ret[b].ContestTime
+		}
+
+		return ret[a].Id < ret[b].Id
+	})
+	return ret
+}
+
+func (m *ContestModel) SubmissionById(submissionId string) (Submission, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.submissions[submissionId]
+	return v, ok
+}
+
+// Judgements returns all judgements, sorted by start contest time
+func (m *ContestModel) Judgements() []Judgement {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Judgement, 0, len(m.judgements))
+	for _, v := range m.judgements {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool {
+		if ret[a].StartContestTime != ret[b].StartContestTime {
+			return ret[a].StartContestTime < ret[b].StartContestTime
+		}
+
+		return ret[a].Id < ret[b].Id
+	})
+	return ret
+}
+
+func (m *ContestModel) JudgementById(judgementId string) (Judgement, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.judgements[judgementId]
+	return v, ok
+}
+
+// Runs returns all runs, sorted by judgement and ordinal
+func (m *ContestModel) Runs() []Run {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Run, 0, len(m.runs))
+	for _, v := range m.runs {
+		ret = append(ret, v)
+	}
+
+	sortRuns(ret)
+	return ret
+}
+
+func (m *ContestModel) RunById(runId string) (Run, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.runs[runId]
+	return v, ok
+}
+
+// JudgementRuns returns the runs of the judgement with the given id, sorted by ordinal
+func (m *ContestModel) JudgementRuns(judgementId string) []Run {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var ret []Run
+	for _, v := range m.runs {
+		if v.JudgementId == judgementId {
+			ret = append(ret, v)
+		}
+	}
+
+	sortRuns(ret)
+	return ret
+}
+
+func sortRuns(runs []Run) {
+	sort.Slice(runs, func(a, b int) bool {
+		if runs[a].JudgementId != runs[b].JudgementId {
+			return runs[a].JudgementId < runs[b].JudgementId
+		}
+		if runs[a].Ordinal != runs[b].Ordinal {
+			return runs[a].Ordinal < runs[b].Ordinal
+		}
+
+		return runs[a].Id < runs[b].Id
+	})
+}
+
+// Clarifications returns all clarifications, sorted by contest time
+func (m *ContestModel) Clarifications() []Clarification {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Clarification, 0, len(m.clarifications))
+	for _, v := range m.clarifications {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool {
+		if ret[a].ContestTime != ret[b].ContestTime {
+			return ret[a].ContestTime < ret[b].ContestTime
+		}
+
+		return ret[a].Id < ret[b].Id
+	})
+	return ret
+}
+
+func (m *ContestModel) ClarificationById(clarificationId string) (Clarification, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.clarifications[clarificationId]
+	return v, ok
+}
+
+// Awards returns all awards, sorted by id
+func (m *ContestModel) Awards() []Award {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Award, 0, len(m.awards))
+	for _, v := range m.awards {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool { return ret[a].Id < ret[b].Id })
+	return ret
+}
+
+func (m *ContestModel) AwardById(awardId string) (Award, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.awards[awardId]
+	return v, ok
+}
+
+// Commentary returns all commentary, sorted by contest time
+func (m *ContestModel) Commentary() []Commentary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]Commentary, 0, len(m.commentary))
+	for _, v := range m.commentary {
+		ret = append(ret, v)
+	}
+
+	sort.Slice(ret, func(a, b int) bool {
+		if ret[a].ContestTime != ret[b].ContestTime {
+			return ret[a].ContestTime < ret[b].ContestTime
+		}
+
+		return ret[a].Id < ret[b].Id
+	})
+	return ret
+}
+
+func (m *ContestModel) CommentaryById(commentaryId string) (Commentary, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.commentary[commentaryId]
+	return v, ok
+}
+
+// ScoreboardInput returns the data in the model needed to compute a scoreboard
+func (m *ContestModel) ScoreboardInput() ScoreboardInput {
+	return ScoreboardInput{
+		Contest:        m.Contest(),
+		State:          m.State(),
+		Problems:       m.Problems(),
+		Groups:         m.Groups(),
+		Teams:          m.Teams(),
+		Submissions:    m.Submissions(),
+		Judgements:     m.Judgements(),
+		JudgementTypes: m.JudgementTypes(),
+	}
+}
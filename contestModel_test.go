@@ -0,0 +1,102 @@
+package interactor
+
+import (
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContestModel_Consume(t *testing.T) {
+	t.Run("2020-03", func(t *testing.T) {
+		m := NewContestModel()
+		assert.Nil(t, m.Consume(NewEventReader(ioutil.NopCloser(strings.NewReader(testFeed2020)))))
+
+		assert.Len(t, m.Problems(), 1)
+		assert.Len(t, m.Submissions(), 0)
+
+		p, ok := m.ProblemById("A")
+		assert.True(t, ok)
+		assert.EqualValues(t, "Apples", p.Name)
+	})
+
+	t.Run("2022-07", func(t *testing.T) {
+		m := NewContestModel()
+		assert.Nil(t, m.Consume(NewEventReader(ioutil.NopCloser(strings.NewReader(testFeed2022)))))
+
+		assert.Len(t, m.Problems(), 0)
+		assert.NotNil(t, m.State().Started)
+	})
+
+	t.Run("2022-07-list", func(t *testing.T) {
+		m := NewContestModel()
+		m.Update(Team{Id: "t0"})
+		m.Update(Problem{Id: "A"})
+
+		feed := `{"type":"teams","id":null,"data":[{"id":"t1"},{"id":"t2"}],"token":"t1"}`
+		assert.Nil(t, m.Consume(NewEventReader(ioutil.NopCloser(strings.NewReader(feed)))))
+
+		// The teams are replaced, other types are not affected
+		teams := m.Teams()
+		if assert.Len(t, teams, 2) {
+			assert.EqualValues(t, "t1", teams[0].Id)
+		}
+		assert.Len(t, m.Problems(), 1)
+	})
+}
+
+func TestContestModel_Update(t *testing.T) {
+	m := NewContestModel()
+	m.Update(Problem{Id: "B", Ordinal: 1})
+	m.Update(Problem{Id: "A", Ordinal: 0})
+	m.Update(Problem{Id: "C", Ordinal: 2})
+	m.Update(Team{Id: "t1", Name: "Team"})
+	m.Update(Team{Id: "t1", Name: "Renamed team"})
+
+	problems := m.Problems()
+	assert.Len(t, problems, 3)
+	assert.EqualValues(t, "A", problems[0].Id)
+	assert.EqualValues(t, "C", problems[2].Id)
+
+	team, ok := m.TeamById("t1")
+	assert.True(t, ok)
+	assert.EqualValues(t, "Renamed team", team.Name)
+
+	m.Update(Person{Id: "p1", TeamId: "t1"})
+	if assert.Len(t, m.Persons(), 1) {
+		assert.EqualValues(t, "t1", m.Persons()[0].TeamId)
+	}
+
+	m.Delete(Problem{}, "B")
+	_, ok = m.ProblemById("B")
+	assert.False(t, ok)
+
+	m.Update(Run{Id: "r2", JudgementId: "j1", Ordinal: 2})
+	m.Update(Run{Id: "r1", JudgementId: "j1", Ordinal: 1})
+	m.Update(Run{Id: "r3", JudgementId: "j2", Ordinal: 1})
+	runs := m.JudgementRuns("j1")
+	if assert.Len(t, runs, 2) {
+		assert.EqualValues(t, "r1", runs[0].Id)
+	}
+
+	// Readers should be able to run concurrently with a writer
+	var wg sync.WaitGroup
+	for k := 0; k < 4; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				_ = m.Submissions()
+			}
+		}()
+	}
+
+	for n := 0; n < 100; n++ {
+		m.Update(Submission{Id: strings.Repeat("s", n+1)})
+	}
+
+	wg.Wait()
+	assert.Len(t, m.Submissions(), 100)
+}
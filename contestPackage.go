@@ -0,0 +1,348 @@
+package interactor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// ExportOptions determine what ExportContest writes besides the objects of the contest
+type ExportOptions struct {
+	// SkipSubmissionFiles disables downloading the files of submissions into submissions/<id>/
+	SkipSubmissionFiles bool
+	// SkipAssets disables downloading files such as logos, photos and problem statements
+	SkipAssets bool
+	// SkipEventFeed disables writing event-feed.ndjson
+	SkipEventFeed bool
+}
+
+// packageEndpoint is a list of objects stored in a contest package as <path>.json
+type packageEndpoint struct {
+	typ ApiType
+	// optional endpoints are not provided by every CCS
+	optional bool
+}
+
+var packageEndpoints = []packageEndpoint{
+	{typ: JudgementType{}},
+	{typ: Language{}},
+	{typ: Problem{}},
+	{typ: Group{}},
+	{typ: Organization{}},
+	{typ: Team{}},
+	{typ: Person{}, optional: true},
+	{typ: Submission{}},
+	{typ: Judgement{}},
+	{typ: Run{}, optional: true},
+	{typ: Clarification{}},
+	{typ: Award{}, optional: true},
+	{typ: Commentary{}, optional: true},
+}
+
+// ExportContest writes the contest of api to dir in the layout of a contest package: api.json, contest.json,
+// state.json, scoreboard.json and a JSON file per endpoint, such as teams.json and submissions.json. Unless disabled
+// by opts the files of submissions are stored as submissions/<id>/files.zip and unpacked next to it, the event feed
+// is written to event-feed.ndjson and files referenced by objects are stored as <endpoint>/<id>/<filename>, or
+// contest/<filename> for the contest. The hrefs of stored files are replaced by their path in the package. Endpoints
+// and the event feed that are not available to the account of api are left out, except for the required endpoints.
+func ExportContest(ctx context.Context, api ContestApi, dir string, opts ExportOptions) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create directory; %w", err)
+	}
+
+	contest, err := api.ContestContext(ctx)
+	if err != nil {
+		return fmt.Errorf("could not export contest; %w", err)
+	}
+
+	state, err := api.StateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("could not export state; %w", err)
+	}
+
+	scoreboard, err := api.ScoreboardContext(ctx)
+	if err != nil {
+		return fmt.Errorf("could not export scoreboard; %w", err)
+	}
+
+	lists := make(map[string][]ApiType, len(packageEndpoints))
+	for _, e := range packageEndpoints {
+		list, err := api.GetObjectsContext(ctx, e.typ)
+		if e.optional && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden)) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not export %s; %w", e.typ.Path(), err)
+		}
+
+		lists[e.typ.Path()] = list
+	}
+
+	if !opts.SkipAssets {
+		x := exporter{ctx: ctx, api: api, dir: dir, used: make(map[string]bool)}
+		contest.Banner = x.files("contest", "banner", contest.Banner)
+		contest.Logo = x.files("contest", "logo", contest.Logo)
+
+		// The endpoints are handled in a fixed order, such that the same error is returned for the same contest
+		for _, e := range packageEndpoints {
+			list := lists[e.typ.Path()]
+			for k, obj := range list {
+				list[k] = x.objectFiles(obj)
+			}
+		}
+
+		if x.err != nil {
+			return x.err
+		}
+	}
+
+	if !opts.SkipSubmissionFiles {
+		for k, obj := range lists["submissions"] {
+			submission, err := exportSubmissionFiles(ctx, api, obj.(Submission), filepath.Join(dir, "submissions"))
+			if err != nil {
+				return err
+			}
+
+			lists["submissions"][k] = submission
+		}
+	}
+
+	// Not every CCS serves the information about the API
+	if info, err := api.InfoContext(ctx); err == nil {
+		if err := writeJSON(filepath.Join(dir, "api.json"), info); err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSON(filepath.Join(dir, "contest.json"), contest); err != nil {
+		return err
+	}
+
+	if err := writeJSON(filepath.Join(dir, "state.json"), state); err != nil {
+		return err
+	}
+
+	if err := writeJSON(filepath.Join(dir, "scoreboard.json"), scoreboard); err != nil {
+		return err
+	}
+
+	for _, e := range packageEndpoints {
+		list, ok := lists[e.typ.Path()]
+		if !ok {
+			continue
+		}
+
+		// An empty list is written as [] instead of null
+		if list == nil {
+			list = []ApiType{}
+		}
+
+		if err := writeJSON(filepath.Join(dir, e.typ.Path()+".json"), list); err != nil {
+			return err
+		}
+	}
+
+	if !opts.SkipEventFeed {
+		if err := exportEventFeed(ctx, api, filepath.Join(dir, "event-feed.ndjson")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exporter downloads the files referenced by objects into a contest package, keeping the first error that occurs
+type exporter struct {
+	ctx context.Context
+	api ContestApi
+	dir string
+	err error
+	// used contains the paths of the stored files
+	used map[string]bool
+}
+
+// objectFiles downloads the files referenced by obj and returns obj with the references replaced
+func (x *exporter) objectFiles(obj ApiType) ApiType {
+	switch v := obj.(type) {
+	case Problem:
+		v.Statement = x.files(path.Join("problems", v.Id), "statement", v.Statement)
+		return v
+	case Organization:
+		v.Logo = x.files(path.Join("organizations", v.Id), "logo", v.Logo)
+		return v
+	case Team:
+		v.Photo = x.files(path.Join("teams", v.Id), "photo", v.Photo)
+		return v
+	case Person:
+		v.Photo = x.files(path.Join("persons", v.Id), "photo", v.Photo)
+		return v
+	}
+
+	return obj
+}
+
+// files downloads refs, which are the value of property, into the directory rel of the package and returns the
+// references to the stored files. References to files the server does not have are kept as they are.
+func (x *exporter) files(rel, property string, refs []FileReference) []FileReference {
+	if x.err != nil || len(refs) == 0 {
+		return refs
+	}
+
+	if !validPathElement(path.Base(rel)) {
+		x.err = fmt.Errorf("invalid id %q", path.Base(rel))
+		return refs
+	}
+
+	ret := make([]FileReference, len(refs))
+	for k, ref := range refs {
+		ret[k] = ref
+
+		data, err := x.api.DownloadContext(x.ctx, ref)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			x.err = fmt.Errorf("could not download %v; %w", ref.Href, err)
+			return refs
+		}
+
+		// Files without a usable name, or with the name of another file, are named after their property instead
+		name, ext := packageFilename(ref), ""
+		if !validPathElement(name) {
+			name = fmt.Sprintf("%s%d", property, k)
+		} else {
+			ext = path.Ext(name)
+		}
+		for n := k; x.used[path.Join(rel, name)]; n++ {
+			name = fmt.Sprintf("%s%d%s", property, n, ext)
+		}
+		x.used[path.Join(rel, name)] = true
+
+		if err := os.MkdirAll(filepath.Join(x.dir, filepath.FromSlash(rel)), 0755); err != nil {
+			x.err = fmt.Errorf("could not create directory; %w", err)
+			return refs
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(x.dir, filepath.FromSlash(rel), name), data, 0644); err != nil {
+			x.err = fmt.Errorf("could not write %v; %w", ref.Href, err)
+			return refs
+		}
+
+		ret[k].Href = path.Join(rel, name)
+		ret[k].Filename = name
+	}
+
+	return ret
+}
+
+// packageFilename returns the name of the file ref refers to, as used in a contest package
+func packageFilename(ref FileReference) string {
+	if ref.Filename != "" {
+		return ref.Filename
+	}
+
+	u, err := url.Parse(ref.Href)
+	if err != nil {
+		return ""
+	}
+
+	return path.Base(u.Path)
+}
+
+// exportSubmissionFiles unpacks the files of submission into dir/<id>/ and stores them as dir/<id>/files.zip, which
+// the returned submission refers to
+func exportSubmissionFiles(ctx context.Context, api ContestApi, submission Submission, dir string) (Submission, error) {
+	// The id is used as a directory name, so it must not be able to escape dir
+	if !validPathElement(submission.Id) {
+		return submission, fmt.Errorf("invalid submission id %q", submission.Id)
+	}
+
+	files, err := api.SubmissionFilesContext(ctx, submission)
+	if err != nil {
+		return submission, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, submission.Id), 0755); err != nil {
+		return submission, fmt.Errorf("could not create directory; %w", err)
+	}
+
+	if err := files.Extract(filepath.Join(dir, submission.Id)); err != nil {
+		return submission, fmt.Errorf("could not extract files of submission %v; %w", submission.Id, err)
+	}
+
+	data, err := files.Zip()
+	if err != nil {
+		return submission, fmt.Errorf("could not pack files of submission %v; %w", submission.Id, err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, submission.Id, "files.zip"), data, 0644); err != nil {
+		return submission, fmt.Errorf("could not write files of submission %v; %w", submission.Id, err)
+	}
+
+	submission.Files = FileReferences{{
+		Href:     path.Join("submissions", submission.Id, "files.zip"),
+		Filename: "files.zip",
+		Mime:     "application/zip",
+	}}
+	return submission, nil
+}
+
+// exportEventFeed writes the complete event feed of api to filename. Nothing is written when the feed is not
+// available to the account.
+func exportEventFeed(ctx context.Context, api ContestApi, filename string) error {
+	feed, err := api.EventFeedContext(ctx, false)
+	if errors.Is(err, ErrForbidden) || errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not export event feed; %w", err)
+	}
+	defer feed.Close()
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("could not export event feed; %w", err)
+	}
+	defer f.Close()
+
+	for {
+		line, err := feed.nextLine()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("could not export event feed; %w", err)
+		}
+
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("could not export event feed; %w", err)
+		}
+	}
+
+	return f.Close()
+}
+
+// writeJSON writes v to filename as indented JSON
+func writeJSON(filename string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode %v; %w", filepath.Base(filename), err)
+	}
+
+	if err := ioutil.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("could not write %v; %w", filepath.Base(filename), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package interactor
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportContest(t *testing.T) {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	api, err := ContestInteractor(server.URL, "admin", "admin", "nwerc18", false)
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "package")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, ExportContest(context.Background(), api, dir, ExportOptions{}))
+
+	for _, name := range []string{"api.json", "contest.json", "state.json", "scoreboard.json", "judgement-types.json",
+		"languages.json", "problems.json", "groups.json", "organizations.json", "teams.json", "persons.json",
+		"submissions.json", "judgements.json", "runs.json", "clarifications.json", "awards.json", "commentary.json",
+		"event-feed.ndjson", "submissions/s3/Main.java"} {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+		assert.Nil(t, err, name)
+	}
+
+	var contest Contest
+	readJSON(t, filepath.Join(dir, "contest.json"), &contest)
+	assert.EqualValues(t, "nwerc18", contest.Id)
+
+	var teams []Team
+	readJSON(t, filepath.Join(dir, "teams.json"), &teams)
+	assert.Len(t, teams, 2)
+
+	// The files of submissions refer to the archive in the package
+	var submissions []Submission
+	readJSON(t, filepath.Join(dir, "submissions.json"), &submissions)
+	for _, submission := range submissions {
+		if assert.Len(t, submission.Files, 1) {
+			assert.EqualValues(t, "submissions/"+submission.Id+"/files.zip", submission.Files[0].Href)
+
+			data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(submission.Files[0].Href)))
+			assert.Nil(t, err)
+
+			var files LocalFileReference
+			assert.Nil(t, files.FromZip(data))
+			assert.NotEmpty(t, files.Names())
+		}
+	}
+
+	// The logos are stored in the package and referred to by their path
+	var organizations []Organization
+	readJSON(t, filepath.Join(dir, "organizations.json"), &organizations)
+	for _, organization := range organizations {
+		if organization.Id != "uva" {
+			continue
+		}
+
+		if assert.Len(t, organization.Logo, 2) {
+			logo := organization.Logo[0]
+			assert.EqualValues(t, "organizations/uva/logo.64x64.png", logo.Href)
+			assert.EqualValues(t, "image/png", logo.Mime)
+
+			data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(logo.Href)))
+			assert.Nil(t, err)
+			assert.EqualValues(t, "\x89PNG uva 64x64", string(data))
+		}
+	}
+
+	feed, err := ioutil.ReadFile(filepath.Join(dir, "event-feed.ndjson"))
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(feed)), "\n")
+	if assert.NotEmpty(t, lines) {
+		e, err := ParseEvent([]byte(lines[0]))
+		assert.Nil(t, err)
+		assert.EqualValues(t, "contest", e.Type)
+	}
+}
+
+func TestExportContestOptions(t *testing.T) {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	api, err := ContestInteractor(server.URL, "admin", "admin", "nwerc18", false)
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "package")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := ExportOptions{SkipSubmissionFiles: true, SkipAssets: true, SkipEventFeed: true}
+	assert.Nil(t, ExportContest(context.Background(), api, dir, opts))
+
+	for _, name := range []string{"event-feed.ndjson", "submissions", "organizations"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+
+	// The references are kept as they are
+	var organizations []Organization
+	readJSON(t, filepath.Join(dir, "organizations.json"), &organizations)
+	for _, organization := range organizations {
+		for _, logo := range organization.Logo {
+			assert.True(t, strings.HasPrefix(logo.Href, "contests/nwerc18/"))
+		}
+	}
+}
+
+func TestExportContestTeam(t *testing.T) {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	api, err := ContestInteractor(server.URL, "team", "team", "nwerc18", false)
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "package")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// A team can not read the event feed, which is left out
+	assert.Nil(t, ExportContest(context.Background(), api, dir, ExportOptions{}))
+
+	_, err = os.Stat(filepath.Join(dir, "event-feed.ndjson"))
+	assert.True(t, os.IsNotExist(err))
+
+	var submissions []Submission
+	readJSON(t, filepath.Join(dir, "submissions.json"), &submissions)
+	for _, submission := range submissions {
+		assert.EqualValues(t, "1", submission.TeamId)
+	}
+}
+
+func TestExportContestFilenames(t *testing.T) {
+	data := DefaultMockData()
+	data.Contests[0].Assets["organizations/uva/logo.svg"] = MockAsset{Mime: "image/svg+xml", Data: []byte("<svg/>")}
+
+	// The third logo would be renamed to the name of the first one
+	data.Contests[0].Organizations[0].Logo = []FileReference{
+		{Href: "contests/nwerc18/organizations/uva/logo.64x64.png", Filename: "logo2.png", Mime: "image/png"},
+		{Href: "contests/nwerc18/organizations/uva/logo.256x256.png", Filename: "logo.png", Mime: "image/png"},
+		{Href: "contests/nwerc18/organizations/uva/logo.svg", Filename: "logo.png", Mime: "image/svg+xml"},
+	}
+
+	server := NewMockServer(data)
+	defer server.Close()
+
+	api, err := ContestInteractor(server.URL, "admin", "admin", "nwerc18", false)
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "package")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := ExportOptions{SkipSubmissionFiles: true, SkipEventFeed: true}
+	assert.Nil(t, ExportContest(context.Background(), api, dir, opts))
+
+	var organizations []Organization
+	readJSON(t, filepath.Join(dir, "organizations.json"), &organizations)
+	for _, organization := range organizations {
+		if organization.Id != "uva" || !assert.Len(t, organization.Logo, 3) {
+			continue
+		}
+
+		expected := map[string]string{
+			"organizations/uva/logo2.png": "\x89PNG uva 64x64",
+			"organizations/uva/logo.png":  "\x89PNG uva 256x256",
+			"organizations/uva/logo3.png": "<svg/>",
+		}
+		for _, logo := range organization.Logo {
+			contents, ok := expected[logo.Href]
+			if assert.True(t, ok, logo.Href) {
+				delete(expected, logo.Href)
+
+				data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(logo.Href)))
+				assert.Nil(t, err)
+				assert.EqualValues(t, contents, string(data))
+			}
+		}
+	}
+}
+
+func readJSON(t *testing.T, filename string, v interface{}) {
+	data, err := ioutil.ReadFile(filename)
+	if assert.Nil(t, err) {
+		assert.Nil(t, json.Unmarshal(data, v))
+	}
+}
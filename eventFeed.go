@@ -0,0 +1,233 @@
+package interactor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type (
+	// Event is a single notification read from the event feed of a contest. Both the 2020-03 / 2021-11 format
+	// ({type, id, op, data}) and the 2022-07+ format ({type, id, data, token}) are supported.
+	Event struct {
+		// Id is the id of the event itself, only set in the 2020-03 / 2021-11 format
+		Id string
+		// Token is the token of the event, only set in the 2022-07+ format
+		Token string
+		// Type is the type of the object, e.g. "submissions"
+		Type string
+		// Op is the operation of the event, in the 2022-07+ format it is derived from the data
+		Op string
+		// ObjectId is the id of the object the event is about, empty for singleton objects such as the state
+		ObjectId string
+		// Data is the decoded object, nil for delete events, events with a list of objects and types unknown to this
+		// package
+		Data ApiType
+		// Objects are the decoded objects of an event that replaces all objects of its type, as sent by 2022-07+
+		// servers with an array as data. It is nil for all other events.
+		Objects []ApiType
+		// Raw contains the line as it was read from the feed
+		Raw json.RawMessage
+	}
+
+	// EventReader reads events from a (possibly streaming) event feed, one line at a time
+	EventReader struct {
+		body   io.ReadCloser
+		reader *bufio.Reader
+	}
+
+	// rawEvent is the wire format of an event, containing the keys of all supported versions
+	rawEvent struct {
+		Type  string          `json:"type"`
+		Id    json.RawMessage `json:"id"`
+		Op    *string         `json:"op"`
+		Token *string         `json:"token"`
+		Data  json.RawMessage `json:"data"`
+	}
+)
+
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// eventTypes maps the types used in the event feed to the ApiType used to decode their data
+var eventTypes = map[string]ApiType{
+	"contest":         Contest{},
+	"contests":        Contest{},
+	"state":           State{},
+	"judgement-types": JudgementType{},
+	"languages":       Language{},
+	"problems":        Problem{},
+	"groups":          Group{},
+	"organizations":   Organization{},
+	"teams":           Team{},
+	"persons":         Person{},
+	"accounts":        Account{},
+	"submissions":     Submission{},
+	"judgements":      Judgement{},
+	"runs":            Run{},
+	"awards":          Award{},
+	"commentary":      Commentary{},
+	"clarifications":  Clarification{},
+}
+
+// NewEventReader constructs an EventReader reading newline-delimited events from r. Closing the EventReader closes r.
+func NewEventReader(r io.ReadCloser) *EventReader {
+	return &EventReader{
+		body:   r,
+		reader: bufio.NewReader(r),
+	}
+}
+
+// Next blocks until the next event is available and returns it. Empty lines, which are sent as keep-alive, are
+// skipped. When the feed ends io.EOF is returned.
+func (r *EventReader) Next() (Event, error) {
+	line, err := r.nextLine()
+	if err != nil {
+		return Event{}, err
+	}
+
+	return ParseEvent(line)
+}
+
+// nextLine returns the next non-empty line of the feed
+func (r *EventReader) nextLine() ([]byte, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			// The line was interrupted, it can not be parsed
+			return nil, err
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			// A final line without trailing newline is still a valid event
+			return line, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close closes the underlying feed
+func (r *EventReader) Close() error {
+	return r.body.Close()
+}
+
+// ParseEvent parses a single line of the event feed
+func ParseEvent(line []byte) (e Event, err error) {
+	var raw rawEvent
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return e, fmt.Errorf("could not parse event; %w", err)
+	}
+
+	e.Type = raw.Type
+	e.Raw = append(json.RawMessage(nil), line...)
+
+	id, err := rawId(raw.Id)
+	if err != nil {
+		return e, fmt.Errorf("could not parse event id; %w", err)
+	}
+
+	hasData := len(raw.Data) > 0 && !bytes.Equal(raw.Data, []byte("null"))
+	isList := hasData && raw.Data[0] == '['
+	if raw.Op != nil {
+		// 2020-03 / 2021-11 format, the id is the id of the event and the object id is part of the data
+		e.Id = id
+		e.Op = *raw.Op
+		if hasData && !isList {
+			var obj struct {
+				Id json.RawMessage `json:"id"`
+			}
+			if err := json.Unmarshal(raw.Data, &obj); err != nil {
+				return e, fmt.Errorf("could not parse event data; %w", err)
+			}
+
+			if e.ObjectId, err = rawId(obj.Id); err != nil {
+				return e, fmt.Errorf("could not parse object id; %w", err)
+			}
+		}
+	} else {
+		// 2022-07+ format, the id is the id of the object and a missing object indicates a deletion
+		e.ObjectId = id
+		if raw.Token != nil {
+			e.Token = *raw.Token
+		}
+
+		e.Op = OpUpdate
+		if !hasData {
+			e.Op = OpDelete
+		}
+	}
+
+	// Deletions in the 2020-03 format still carry the id of the object, it should not be decoded as a full object
+	if !hasData || e.Op == OpDelete {
+		return e, nil
+	}
+
+	typ, ok := eventTypes[e.Type]
+	if !ok {
+		// Unknown types are passed on without data, the raw line can still be used
+		return e, nil
+	}
+
+	if isList {
+		// The event contains all objects of its type, which replace the objects that are currently known
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw.Data, &items); err != nil {
+			return e, fmt.Errorf("could not decode %s event; %w", e.Type, err)
+		}
+
+		e.Objects = make([]ApiType, len(items))
+		for k, item := range items {
+			if e.Objects[k], err = typ.FromJSON(item); err != nil {
+				return e, fmt.Errorf("could not decode %s event; %w", e.Type, err)
+			}
+		}
+
+		return e, nil
+	}
+
+	if e.Data, err = typ.FromJSON(raw.Data); err != nil {
+		return e, fmt.Errorf("could not decode %s event; %w", e.Type, err)
+	}
+
+	return e, nil
+}
+
+// rawId decodes an id which can either be a string, a number or null
+func rawId(data json.RawMessage) (string, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s, nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", err
+	}
+
+	return n.String(), nil
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf(`
+       id: %v
+    token: %v
+     type: %v
+       op: %v
+object id: %v
+     data: %v
+  objects: %v
+`, e.Id, e.Token, e.Type, e.Op, e.ObjectId, e.Data, len(e.Objects))
+}
@@ -0,0 +1,140 @@
+package interactor
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testFeed2020 = `{"type":"problems","id":"1","op":"create","data":{"id":"A","label":"A","name":"Apples","ordinal":0}}
+
+{"type":"submissions","id":"2","op":"create","data":{"id":"s1","language_id":"cpp","team_id":"t1","problem_id":"A"}}
+{"type":"submissions","id":"3","op":"delete","data":{"id":"s1"}}
+`
+	testFeed2022 = `{"type":"problems","id":"A","data":{"id":"A","label":"A","name":"Apples","ordinal":0},"token":"t1"}
+
+{"type":"state","id":null,"data":{"started":"2021-01-01T10:00:00Z"},"token":"t2"}
+{"type":"awesome-new-type","id":"x","data":{"id":"x"},"token":"t3"}
+{"type":"problems","id":"A","data":null,"token":"t4"}`
+)
+
+func TestParseEvent(t *testing.T) {
+	t.Run("2020-03", func(t *testing.T) {
+		e, err := ParseEvent([]byte(`{"type":"judgements","id":"42","op":"update","data":{"id":"17","submission_id":"s1"}}`))
+		assert.Nil(t, err)
+		assert.EqualValues(t, "42", e.Id)
+		assert.EqualValues(t, "", e.Token)
+		assert.EqualValues(t, OpUpdate, e.Op)
+		assert.EqualValues(t, "17", e.ObjectId)
+		assert.IsType(t, Judgement{}, e.Data)
+	})
+
+	t.Run("2022-07", func(t *testing.T) {
+		e, err := ParseEvent([]byte(`{"type":"teams","id":"t1","data":{"id":"t1","name":"Team"},"token":"abc"}`))
+		assert.Nil(t, err)
+		assert.EqualValues(t, "", e.Id)
+		assert.EqualValues(t, "abc", e.Token)
+		assert.EqualValues(t, OpUpdate, e.Op)
+		assert.EqualValues(t, "t1", e.ObjectId)
+		assert.EqualValues(t, "Team", e.Data.(Team).Name)
+	})
+
+	t.Run("2022-07-list", func(t *testing.T) {
+		e, err := ParseEvent([]byte(`{"type":"teams","id":null,"data":[{"id":"t1","name":"Team"},{"id":"t2"}],"token":"abc"}`))
+		assert.Nil(t, err)
+		assert.EqualValues(t, OpUpdate, e.Op)
+		assert.EqualValues(t, "", e.ObjectId)
+		assert.Nil(t, e.Data)
+		if assert.Len(t, e.Objects, 2) {
+			assert.EqualValues(t, "Team", e.Objects[0].(Team).Name)
+			assert.EqualValues(t, "t2", e.Objects[1].(Team).Id)
+		}
+
+		e, err = ParseEvent([]byte(`{"type":"teams","id":null,"data":[],"token":"abd"}`))
+		assert.Nil(t, err)
+		assert.NotNil(t, e.Objects)
+		assert.Len(t, e.Objects, 0)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseEvent([]byte(`{"type":`))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestEventReader(t *testing.T) {
+	t.Run("2020-03", func(t *testing.T) {
+		events := readAllEvents(t, NewEventReader(ioutil.NopCloser(strings.NewReader(testFeed2020))))
+		assert.Len(t, events, 3)
+		assert.EqualValues(t, OpCreate, events[0].Op)
+		assert.EqualValues(t, "Apples", events[0].Data.(Problem).Name)
+		assert.EqualValues(t, "s1", events[1].Data.(Submission).Id)
+		assert.EqualValues(t, OpDelete, events[2].Op)
+		assert.EqualValues(t, "s1", events[2].ObjectId)
+		assert.Nil(t, events[2].Data)
+	})
+
+	t.Run("2022-07", func(t *testing.T) {
+		events := readAllEvents(t, NewEventReader(ioutil.NopCloser(strings.NewReader(testFeed2022))))
+		assert.Len(t, events, 4)
+		assert.EqualValues(t, "t1", events[0].Token)
+		assert.IsType(t, State{}, events[1].Data)
+		assert.EqualValues(t, "", events[1].ObjectId)
+
+		// Unknown types are passed on without data
+		assert.EqualValues(t, "awesome-new-type", events[2].Type)
+		assert.Nil(t, events[2].Data)
+		assert.NotEmpty(t, events[2].Raw)
+
+		assert.EqualValues(t, OpDelete, events[3].Op)
+		assert.EqualValues(t, "A", events[3].ObjectId)
+	})
+}
+
+func TestEventFeed(t *testing.T) {
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/event-feed":
+			assert.EqualValues(t, "false", r.URL.Query().Get("stream"))
+			_, _ = w.Write([]byte(testFeed2022))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ser.Close()
+
+	api, err := ContestInteractor(ser.URL, "", "", "test", false)
+	assert.Nil(t, err)
+
+	feed, err := api.EventFeed(false)
+	assert.Nil(t, err)
+	defer feed.Close()
+
+	events := readAllEvents(t, feed)
+	assert.Len(t, events, 4)
+}
+
+func readAllEvents(t *testing.T, r *EventReader) []Event {
+	var events []Event
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return events
+		}
+
+		assert.Nil(t, err)
+		if err != nil {
+			return events
+		}
+
+		events = append(events, e)
+	}
+}
@@ -2,14 +2,26 @@ package interactor
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 func (i inter) Contests() ([]Contest, error) {
-	obj, err := i.GetObjects(Contest{})
+	return i.ContestsContext(context.Background())
+}
+
+func (i inter) ContestsContext(ctx context.Context) ([]Contest, error) {
+	obj, err := i.GetObjectsContext(ctx, Contest{})
 	if err != nil {
 		return nil, err
 	}
@@ -28,8 +40,43 @@ func (i inter) Contests() ([]Contest, error) {
 	return ret, nil
 }
 
+func (i inter) Info() (ApiInfo, error) {
+	return i.InfoContext(context.Background())
+}
+
+func (i inter) InfoContext(ctx context.Context) (ApiInfo, error) {
+	if i.info != nil {
+		i.info.mu.Lock()
+		defer i.info.mu.Unlock()
+
+		if i.info.info != nil {
+			return *i.info.info, nil
+		}
+	}
+
+	objs, err := i.retrieve(ctx, ApiInfo{}, "", true)
+	if err != nil {
+		return ApiInfo{}, err
+	}
+
+	info, ok := objs[0].(ApiInfo)
+	if !ok {
+		return info, fmt.Errorf("expected api info, got: %T", objs[0])
+	}
+
+	if i.info != nil {
+		i.info.info = &info
+	}
+
+	return info, nil
+}
+
 func (i inter) ContestById(contestId string) (c Contest, err error) {
-	obj, err := i.GetObject(c, contestId)
+	return i.ContestByIdContext(context.Background(), contestId)
+}
+
+func (i inter) ContestByIdContext(ctx context.Context, contestId string) (c Contest, err error) {
+	obj, err := i.GetObjectContext(ctx, c, contestId)
 	if err != nil {
 		return c, err
 	}
@@ -44,11 +91,19 @@ func (i inter) ContestById(contestId string) (c Contest, err error) {
 }
 
 func (i inter) Contest() (c Contest, err error) {
-	return i.ContestById(i.contestId)
+	return i.ContestContext(context.Background())
+}
+
+func (i inter) ContestContext(ctx context.Context) (c Contest, err error) {
+	return i.ContestByIdContext(ctx, i.contestId)
 }
 
 func (i inter) Persons() ([]Person, error) {
-	obj, err := i.GetObjects(Person{})
+	return i.PersonsContext(context.Background())
+}
+
+func (i inter) PersonsContext(ctx context.Context) ([]Person, error) {
+	obj, err := i.GetObjectsContext(ctx, Person{})
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +123,11 @@ func (i inter) Persons() ([]Person, error) {
 }
 
 func (i inter) PersonById(personId string) (p Person, err error) {
-	obj, err := i.GetObject(p, personId)
+	return i.PersonByIdContext(context.Background(), personId)
+}
+
+func (i inter) PersonByIdContext(ctx context.Context, personId string) (p Person, err error) {
+	obj, err := i.GetObjectContext(ctx, p, personId)
 	if err != nil {
 		return p, err
 	}
@@ -83,7 +142,11 @@ func (i inter) PersonById(personId string) (p Person, err error) {
 }
 
 func (i inter) Accounts() ([]Account, error) {
-	obj, err := i.GetObjects(Account{})
+	return i.AccountsContext(context.Background())
+}
+
+func (i inter) AccountsContext(ctx context.Context) ([]Account, error) {
+	obj, err := i.GetObjectsContext(ctx, Account{})
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +166,11 @@ func (i inter) Accounts() ([]Account, error) {
 }
 
 func (i inter) AccountById(accountId string) (a Account, err error) {
-	obj, err := i.GetObject(a, accountId)
+	return i.AccountByIdContext(context.Background(), accountId)
+}
+
+func (i inter) AccountByIdContext(ctx context.Context, accountId string) (a Account, err error) {
+	obj, err := i.GetObjectContext(ctx, a, accountId)
 	if err != nil {
 		return a, err
 	}
@@ -118,7 +185,11 @@ func (i inter) AccountById(accountId string) (a Account, err error) {
 }
 
 func (i inter) Account() (a Account, err error) {
-	objs, err := i.retrieve(Account{}, "contests/"+i.contestId+"/account", true)
+	return i.AccountContext(context.Background())
+}
+
+func (i inter) AccountContext(ctx context.Context) (a Account, err error) {
+	objs, err := i.retrieve(ctx, Account{}, "contests/"+i.contestId+"/account", true)
 
 	if err != nil {
 		return a, err
@@ -138,7 +209,11 @@ func (i inter) Account() (a Account, err error) {
 }
 
 func (i inter) Problems() ([]Problem, error) {
-	obj, err := i.GetObjects(Problem{})
+	return i.ProblemsContext(context.Background())
+}
+
+func (i inter) ProblemsContext(ctx context.Context) ([]Problem, error) {
+	obj, err := i.GetObjectsContext(ctx, Problem{})
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +233,11 @@ func (i inter) Problems() ([]Problem, error) {
 }
 
 func (i inter) ProblemById(problemId string) (p Problem, err error) {
-	obj, err := i.GetObject(p, problemId)
+	return i.ProblemByIdContext(context.Background(), problemId)
+}
+
+func (i inter) ProblemByIdContext(ctx context.Context, problemId string) (p Problem, err error) {
+	obj, err := i.GetObjectContext(ctx, p, problemId)
 	if err != nil {
 		return p, err
 	}
@@ -173,7 +252,11 @@ func (i inter) ProblemById(problemId string) (p Problem, err error) {
 }
 
 func (i inter) Submissions() ([]Submission, error) {
-	obj, err := i.GetObjects(Submission{})
+	return i.SubmissionsContext(context.Background())
+}
+
+func (i inter) SubmissionsContext(ctx context.Context) ([]Submission, error) {
+	obj, err := i.GetObjectsContext(ctx, Submission{})
 	if err != nil {
 		return nil, err
 	}
@@ -193,7 +276,11 @@ func (i inter) Submissions() ([]Submission, error) {
 }
 
 func (i inter) SubmissionById(submissionId string) (s Submission, err error) {
-	obj, err := i.GetObject(s, submissionId)
+	return i.SubmissionByIdContext(context.Background(), submissionId)
+}
+
+func (i inter) SubmissionByIdContext(ctx context.Context, submissionId string) (s Submission, err error) {
+	obj, err := i.GetObjectContext(ctx, s, submissionId)
 	if err != nil {
 		return s, err
 	}
@@ -207,8 +294,39 @@ func (i inter) SubmissionById(submissionId string) (s Submission, err error) {
 	return
 }
 
+// SubmissionFiles downloads the files of submission and unpacks them
+func (i inter) SubmissionFiles(submission Submission) (LocalFileReference, error) {
+	return i.SubmissionFilesContext(context.Background(), submission)
+}
+
+func (i inter) SubmissionFilesContext(ctx context.Context, submission Submission) (files LocalFileReference, err error) {
+	// Servers that do not list the files still serve them at the standard location
+	ref := FileReference{Href: i.toPath(submission) + "/" + url.PathEscape(submission.Id) + "/files"}
+	for _, f := range submission.Files {
+		if f.Href != "" {
+			ref = f
+			break
+		}
+	}
+
+	data, err := i.DownloadContext(ctx, ref)
+	if err != nil {
+		return files, fmt.Errorf("could not download files of submission %v; %w", submission.Id, err)
+	}
+
+	if err := files.FromZip(data); err != nil {
+		return files, fmt.Errorf("could not unpack files of submission %v; %w", submission.Id, err)
+	}
+
+	return files, nil
+}
+
 func (i inter) Languages() ([]Language, error) {
-	obj, err := i.GetObjects(Language{})
+	return i.LanguagesContext(context.Background())
+}
+
+func (i inter) LanguagesContext(ctx context.Context) ([]Language, error) {
+	obj, err := i.GetObjectsContext(ctx, Language{})
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +346,11 @@ func (i inter) Languages() ([]Language, error) {
 }
 
 func (i inter) LanguageById(languageId string) (l Language, err error) {
-	obj, err := i.GetObject(l, languageId)
+	return i.LanguageByIdContext(context.Background(), languageId)
+}
+
+func (i inter) LanguageByIdContext(ctx context.Context, languageId string) (l Language, err error) {
+	obj, err := i.GetObjectContext(ctx, l, languageId)
 	if err != nil {
 		return l, err
 	}
@@ -243,7 +365,11 @@ func (i inter) LanguageById(languageId string) (l Language, err error) {
 }
 
 func (i inter) JudgementTypes() ([]JudgementType, error) {
-	obj, err := i.GetObjects(JudgementType{})
+	return i.JudgementTypesContext(context.Background())
+}
+
+func (i inter) JudgementTypesContext(ctx context.Context) ([]JudgementType, error) {
+	obj, err := i.GetObjectsContext(ctx, JudgementType{})
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +389,11 @@ func (i inter) JudgementTypes() ([]JudgementType, error) {
 }
 
 func (i inter) JudgementTypeById(judgementTypeId string) (jt JudgementType, err error) {
-	obj, err := i.GetObject(jt, judgementTypeId)
+	return i.JudgementTypeByIdContext(context.Background(), judgementTypeId)
+}
+
+func (i inter) JudgementTypeByIdContext(ctx context.Context, judgementTypeId string) (jt JudgementType, err error) {
+	obj, err := i.GetObjectContext(ctx, jt, judgementTypeId)
 	if err != nil {
 		return jt, err
 	}
@@ -278,7 +408,11 @@ func (i inter) JudgementTypeById(judgementTypeId string) (jt JudgementType, err
 }
 
 func (i inter) Judgements() ([]Judgement, error) {
-	obj, err := i.GetObjects(Judgement{})
+	return i.JudgementsContext(context.Background())
+}
+
+func (i inter) JudgementsContext(ctx context.Context) ([]Judgement, error) {
+	obj, err := i.GetObjectsContext(ctx, Judgement{})
 	if err != nil {
 		return nil, err
 	}
@@ -298,7 +432,11 @@ func (i inter) Judgements() ([]Judgement, error) {
 }
 
 func (i inter) JudgementById(judgementId string) (j Judgement, err error) {
-	obj, err := i.GetObject(j, judgementId)
+	return i.JudgementByIdContext(context.Background(), judgementId)
+}
+
+func (i inter) JudgementByIdContext(ctx context.Context, judgementId string) (j Judgement, err error) {
+	obj, err := i.GetObjectContext(ctx, j, judgementId)
 	if err != nil {
 		return j, err
 	}
@@ -312,8 +450,88 @@ func (i inter) JudgementById(judgementId string) (j Judgement, err error) {
 	return
 }
 
+func (i inter) Runs() ([]Run, error) {
+	return i.RunsContext(context.Background())
+}
+
+func (i inter) RunsContext(ctx context.Context) ([]Run, error) {
+	obj, err := i.GetObjectsContext(ctx, Run{})
+	if err != nil {
+		return nil, err
+	}
+
+	// obj should be a slice of Run, cast to it to slice of Run
+	ret := make([]Run, len(obj))
+	for k, v := range obj {
+		vv, ok := v.(Run)
+		if !ok {
+			return ret, fmt.Errorf("expected run, got: %T", v)
+		}
+
+		ret[k] = vv
+	}
+
+	return ret, nil
+}
+
+func (i inter) RunById(runId string) (r Run, err error) {
+	return i.RunByIdContext(context.Background(), runId)
+}
+
+func (i inter) RunByIdContext(ctx context.Context, runId string) (r Run, err error) {
+	obj, err := i.GetObjectContext(ctx, r, runId)
+	if err != nil {
+		return r, err
+	}
+
+	vv, ok := obj.(Run)
+	if !ok {
+		return r, fmt.Errorf("expected run, got: %T", obj)
+	}
+
+	r = vv
+	return
+}
+
+// JudgementRuns returns the runs of the judgement with the given id, sorted by ordinal
+func (i inter) JudgementRuns(judgementId string) ([]Run, error) {
+	return i.JudgementRunsContext(context.Background(), judgementId)
+}
+
+func (i inter) JudgementRunsContext(ctx context.Context, judgementId string) ([]Run, error) {
+	objs, err := i.retrieve(ctx, Run{}, i.toPath(Run{})+"?judgement_id="+url.QueryEscape(judgementId), false)
+	if errors.Is(err, ErrBadRequest) {
+		// A CCS that does not support the filter may reject it, in which case all runs are retrieved
+		objs, err = i.GetObjectsContext(ctx, Run{})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// A CCS may also ignore the filter, so the runs are filtered here as well
+	var ret []Run
+	for _, v := range objs {
+		r, ok := v.(Run)
+		if !ok {
+			return nil, fmt.Errorf("expected run, got: %T", v)
+		}
+
+		if r.JudgementId == judgementId {
+			ret = append(ret, r)
+		}
+	}
+
+	sort.SliceStable(ret, func(a, b int) bool { return ret[a].Ordinal < ret[b].Ordinal })
+	return ret, nil
+}
+
 func (i inter) Clarifications() ([]Clarification, error) {
-	obj, err := i.GetObjects(Clarification{})
+	return i.ClarificationsContext(context.Background())
+}
+
+func (i inter) ClarificationsContext(ctx context.Context) ([]Clarification, error) {
+	obj, err := i.GetObjectsContext(ctx, Clarification{})
 	if err != nil {
 		return nil, err
 	}
@@ -333,7 +551,11 @@ func (i inter) Clarifications() ([]Clarification, error) {
 }
 
 func (i inter) ClarificationById(clarificationId string) (c Clarification, err error) {
-	obj, err := i.GetObject(c, clarificationId)
+	return i.ClarificationByIdContext(context.Background(), clarificationId)
+}
+
+func (i inter) ClarificationByIdContext(ctx context.Context, clarificationId string) (c Clarification, err error) {
+	obj, err := i.GetObjectContext(ctx, c, clarificationId)
 	if err != nil {
 		return c, err
 	}
@@ -348,7 +570,11 @@ func (i inter) ClarificationById(clarificationId string) (c Clarification, err e
 }
 
 func (i inter) Groups() ([]Group, error) {
-	obj, err := i.GetObjects(Group{})
+	return i.GroupsContext(context.Background())
+}
+
+func (i inter) GroupsContext(ctx context.Context) ([]Group, error) {
+	obj, err := i.GetObjectsContext(ctx, Group{})
 	if err != nil {
 		return nil, err
 	}
@@ -368,7 +594,11 @@ func (i inter) Groups() ([]Group, error) {
 }
 
 func (i inter) GroupById(groupId string) (g Group, err error) {
-	obj, err := i.GetObject(g, groupId)
+	return i.GroupByIdContext(context.Background(), groupId)
+}
+
+func (i inter) GroupByIdContext(ctx context.Context, groupId string) (g Group, err error) {
+	obj, err := i.GetObjectContext(ctx, g, groupId)
 	if err != nil {
 		return g, err
 	}
@@ -383,7 +613,11 @@ func (i inter) GroupById(groupId string) (g Group, err error) {
 }
 
 func (i inter) Organizations() ([]Organization, error) {
-	obj, err := i.GetObjects(Organization{})
+	return i.OrganizationsContext(context.Background())
+}
+
+func (i inter) OrganizationsContext(ctx context.Context) ([]Organization, error) {
+	obj, err := i.GetObjectsContext(ctx, Organization{})
 	if err != nil {
 		return nil, err
 	}
@@ -403,7 +637,11 @@ func (i inter) Organizations() ([]Organization, error) {
 }
 
 func (i inter) OrganizationById(organizationId string) (o Organization, err error) {
-	obj, err := i.GetObject(o, organizationId)
+	return i.OrganizationByIdContext(context.Background(), organizationId)
+}
+
+func (i inter) OrganizationByIdContext(ctx context.Context, organizationId string) (o Organization, err error) {
+	obj, err := i.GetObjectContext(ctx, o, organizationId)
 	if err != nil {
 		return o, err
 	}
@@ -418,7 +656,11 @@ func (i inter) OrganizationById(organizationId string) (o Organization, err erro
 }
 
 func (i inter) Teams() ([]Team, error) {
-	obj, err := i.GetObjects(Team{})
+	return i.TeamsContext(context.Background())
+}
+
+func (i inter) TeamsContext(ctx context.Context) ([]Team, error) {
+	obj, err := i.GetObjectsContext(ctx, Team{})
 	if err != nil {
 		return nil, err
 	}
@@ -438,7 +680,11 @@ func (i inter) Teams() ([]Team, error) {
 }
 
 func (i inter) TeamById(teamId string) (t Team, err error) {
-	obj, err := i.GetObject(t, teamId)
+	return i.TeamByIdContext(context.Background(), teamId)
+}
+
+func (i inter) TeamByIdContext(ctx context.Context, teamId string) (t Team, err error) {
+	obj, err := i.GetObjectContext(ctx, t, teamId)
 	if err != nil {
 		return t, err
 	}
@@ -452,8 +698,98 @@ func (i inter) TeamById(teamId string) (t Team, err error) {
 	return
 }
 
+func (i inter) Commentary() ([]Commentary, error) {
+	return i.CommentaryContext(context.Background())
+}
+
+func (i inter) CommentaryContext(ctx context.Context) ([]Commentary, error) {
+	obj, err := i.GetObjectsContext(ctx, Commentary{})
+	if err != nil {
+		return nil, err
+	}
+
+	// obj should be a slice of Commentary, cast to it to slice of Commentary
+	ret := make([]Commentary, len(obj))
+	for k, v := range obj {
+		vv, ok := v.(Commentary)
+		if !ok {
+			return ret, fmt.Errorf("expected commentary, got: %T", v)
+		}
+
+		ret[k] = vv
+	}
+
+	return ret, nil
+}
+
+func (i inter) CommentaryById(commentaryId string) (c Commentary, err error) {
+	return i.CommentaryByIdContext(context.Background(), commentaryId)
+}
+
+func (i inter) CommentaryByIdContext(ctx context.Context, commentaryId string) (c Commentary, err error) {
+	obj, err := i.GetObjectContext(ctx, c, commentaryId)
+	if err != nil {
+		return c, err
+	}
+
+	vv, ok := obj.(Commentary)
+	if !ok {
+		return c, fmt.Errorf("expected commentary, got: %T", obj)
+	}
+
+	c = vv
+	return
+}
+
+func (i inter) Awards() ([]Award, error) {
+	return i.AwardsContext(context.Background())
+}
+
+func (i inter) AwardsContext(ctx context.Context) ([]Award, error) {
+	obj, err := i.GetObjectsContext(ctx, Award{})
+	if err != nil {
+		return nil, err
+	}
+
+	// obj should be a slice of Award, cast to it to slice of Award
+	ret := make([]Award, len(obj))
+	for k, v := range obj {
+		vv, ok := v.(Award)
+		if !ok {
+			return ret, fmt.Errorf("expected award, got: %T", v)
+		}
+
+		ret[k] = vv
+	}
+
+	return ret, nil
+}
+
+func (i inter) AwardById(awardId string) (a Award, err error) {
+	return i.AwardByIdContext(context.Background(), awardId)
+}
+
+func (i inter) AwardByIdContext(ctx context.Context, awardId string) (a Award, err error) {
+	obj, err := i.GetObjectContext(ctx, a, awardId)
+	if err != nil {
+		return a, err
+	}
+
+	vv, ok := obj.(Award)
+	if !ok {
+		return a, fmt.Errorf("expected award, got: %T", obj)
+	}
+
+	a = vv
+	return
+}
+
 func (i inter) Scoreboard() (s Scoreboard, err error) {
-	obj, err := i.GetObject(s, "")
+	return i.ScoreboardContext(context.Background())
+}
+
+func (i inter) ScoreboardContext(ctx context.Context) (s Scoreboard, err error) {
+	obj, err := i.GetObjectContext(ctx, s, "")
 	if err != nil {
 		return s, err
 	}
@@ -468,7 +804,11 @@ func (i inter) Scoreboard() (s Scoreboard, err error) {
 }
 
 func (i inter) State() (s State, err error) {
-	obj, err := i.GetObject(s, "")
+	return i.StateContext(context.Background())
+}
+
+func (i inter) StateContext(ctx context.Context) (s State, err error) {
+	obj, err := i.GetObjectContext(ctx, s, "")
 	if err != nil {
 		return s, err
 	}
@@ -482,8 +822,76 @@ func (i inter) State() (s State, err error) {
 	return
 }
 
+func (i inter) Access() (a Access, err error) {
+	return i.AccessContext(context.Background())
+}
+
+func (i inter) AccessContext(ctx context.Context) (a Access, err error) {
+	obj, err := i.GetObjectContext(ctx, a, "")
+	if err != nil {
+		return a, err
+	}
+
+	vv, ok := obj.(Access)
+	if !ok {
+		return a, fmt.Errorf("expected access, got: %T", obj)
+	}
+
+	a = vv
+	return
+}
+
+// EventFeed opens the event feed of the contest. When stream is false the server closes the feed after all current
+// events have been sent, otherwise it is kept open and new events are delivered as they happen.
+func (i inter) EventFeed(stream bool) (*EventReader, error) {
+	return i.EventFeedContext(context.Background(), stream)
+}
+
+func (i inter) EventFeedContext(ctx context.Context, stream bool) (*EventReader, error) {
+	return i.EventFeedSinceContext(ctx, EventPosition{}, stream)
+}
+
+// EventFeedSince opens the event feed of the contest, only returning the events after the given position
+func (i inter) EventFeedSince(since EventPosition, stream bool) (*EventReader, error) {
+	return i.EventFeedSinceContext(context.Background(), since, stream)
+}
+
+func (i inter) EventFeedSinceContext(ctx context.Context, since EventPosition, stream bool) (*EventReader, error) {
+	query := url.Values{}
+	query.Set("stream", strconv.FormatBool(stream))
+	if since.Token != "" {
+		query.Set("since_token", since.Token)
+	} else if since.Id != "" {
+		query.Set("since_id", since.Id)
+	}
+
+	body, err := i.open(ctx, "contests/"+i.contestId+"/event-feed?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEventReader(body), nil
+}
+
+// Subscribe returns a Subscription to the streaming event feed of the contest, starting after the given position. The
+// feed is only opened on the first call to Next of the Subscription. The Subscription created by SubscribeContext is
+// closed when ctx is done.
+func (i inter) Subscribe(since EventPosition) *Subscription {
+	return i.SubscribeContext(context.Background(), since)
+}
+
+func (i inter) SubscribeContext(ctx context.Context, since EventPosition) *Subscription {
+	return newSubscription(ctx, since, func(since EventPosition) (*EventReader, error) {
+		return i.EventFeedSinceContext(ctx, since, true)
+	})
+}
+
 func (i inter) PostClarification(problemId, text string) (c Clarification, err error) {
-	obj, err := i.post(c, Clarification{
+	return i.PostClarificationContext(context.Background(), problemId, text)
+}
+
+func (i inter) PostClarificationContext(ctx context.Context, problemId, text string) (c Clarification, err error) {
+	obj, err := i.post(ctx, c, Clarification{
 		ProblemId: problemId,
 		Text:      text,
 	})
@@ -500,8 +908,43 @@ func (i inter) PostClarification(problemId, text string) (c Clarification, err e
 	return
 }
 
+// PostJuryClarification sends a clarification as the jury, which requires an admin account. A clarification with
+// ToTeamId set is only sent to that team, otherwise it is broadcast to all teams. ReplyToId can be set to answer a
+// question, see ClarificationReply. When clarification.Id is set the clarification is created with that id using PUT.
+func (i inter) PostJuryClarification(clarification Clarification) (Clarification, error) {
+	return i.PostJuryClarificationContext(context.Background(), clarification)
+}
+
+func (i inter) PostJuryClarificationContext(ctx context.Context, clarification Clarification) (c Clarification, err error) {
+	if clarification.Text == "" {
+		return c, errors.New("a clarification requires text")
+	}
+
+	var obj ApiType
+	if clarification.Id != "" {
+		obj, err = i.put(ctx, c, clarification.Id, clarification)
+	} else {
+		obj, err = i.post(ctx, c, clarification)
+	}
+	if err != nil {
+		return c, err
+	}
+
+	vv, ok := obj.(Clarification)
+	if !ok {
+		return c, fmt.Errorf("expected clarification, got: %T", obj)
+	}
+
+	c = vv
+	return
+}
+
 func (i inter) PostSubmission(problemId, languageId, entrypoint string, files LocalFileReference) (s Submission, err error) {
-	obj, err := i.post(s, Submission{
+	return i.PostSubmissionContext(context.Background(), problemId, languageId, entrypoint, files)
+}
+
+func (i inter) PostSubmissionContext(ctx context.Context, problemId, languageId, entrypoint string, files LocalFileReference) (s Submission, err error) {
+	obj, err := i.post(ctx, s, Submission{
 		ProblemId:  problemId,
 		LanguageId: languageId,
 		EntryPoint: entrypoint,
@@ -525,12 +968,62 @@ func (i inter) PostSubmission(problemId, languageId, entrypoint string, files Lo
 	return
 }
 
+// PostProxySubmission submits on behalf of the team with id submission.TeamId, which requires an admin account. When
+// submission.Time is set it is used as submission time, when submission.Id is set the submission is created with that
+// id using PUT. The files of the submission should contain the data to submit. An error wrapping ErrForbidden is
+// returned when the account is not allowed to submit on behalf of teams, without submitting anything.
+func (i inter) PostProxySubmission(submission Submission) (Submission, error) {
+	return i.PostProxySubmissionContext(context.Background(), submission)
+}
+
+func (i inter) PostProxySubmissionContext(ctx context.Context, submission Submission) (s Submission, err error) {
+	if submission.TeamId == "" {
+		return s, errors.New("a team id is required to submit on behalf of a team")
+	}
+
+	account, err := i.AccountContext(ctx)
+	if err != nil {
+		return s, fmt.Errorf("could not retrieve account; %w", err)
+	}
+
+	if account.Type != "admin" {
+		return s, fmt.Errorf("account %q of type %q can not submit on behalf of teams; %w", account.Username,
+			account.Type, ErrForbidden)
+	}
+
+	var obj ApiType
+	if submission.Id != "" {
+		obj, err = i.put(ctx, s, submission.Id, submission)
+	} else {
+		obj, err = i.post(ctx, s, submission)
+	}
+	if err != nil {
+		return s, err
+	}
+
+	vv, ok := obj.(Submission)
+	if !ok {
+		return s, fmt.Errorf("expected submission, got: %T", obj)
+	}
+
+	s = vv
+	return
+}
+
 func (i inter) Submit(s Submittable) (ApiType, error) {
-	return i.post(s, s)
+	return i.SubmitContext(context.Background(), s)
+}
+
+func (i inter) SubmitContext(ctx context.Context, s Submittable) (ApiType, error) {
+	return i.post(ctx, s, s)
 }
 
 func (i inter) GetObject(interactor ApiType, id string) (ApiType, error) {
-	objs, err := i.retrieve(interactor, i.toPath(interactor)+"/"+id, true)
+	return i.GetObjectContext(context.Background(), interactor, id)
+}
+
+func (i inter) GetObjectContext(ctx context.Context, interactor ApiType, id string) (ApiType, error) {
+	objs, err := i.retrieve(ctx, interactor, i.toPath(interactor)+"/"+id, true)
 
 	if err != nil {
 		return nil, err
@@ -553,11 +1046,17 @@ func (i inter) toPath(interactor ApiType) string {
 }
 
 func (i inter) GetObjects(interactor ApiType) ([]ApiType, error) {
-	return i.retrieve(interactor, i.toPath(interactor), false)
+	return i.GetObjectsContext(context.Background(), interactor)
 }
 
-func (i inter) retrieve(interactor ApiType, path string, single bool) ([]ApiType, error) {
-	resp, err := i.Get(i.baseUrl + path)
+func (i inter) GetObjectsContext(ctx context.Context, interactor ApiType) ([]ApiType, error) {
+	return i.retrieve(ctx, interactor, i.toPath(interactor), false)
+}
+
+func (i inter) retrieve(ctx context.Context, interactor ApiType, path string, single bool) ([]ApiType, error) {
+	// A cached response is revalidated, instead of retrieved again
+	cached := i.cache.lookup(path)
+	resp, err := i.get(ctx, path, cached.header())
 	if err != nil {
 		return nil, err
 	}
@@ -565,13 +1064,28 @@ func (i inter) retrieve(interactor ApiType, path string, single bool) ([]ApiType
 	// Body is not-nil, ensure it will always be closed
 	defer resp.Body.Close()
 
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		return cached.objects(), nil
+	}
+
 	if err := responseToError(resp); err != nil {
 		return nil, err
 	}
 
+	objs, err := decode(interactor, resp.Body, single)
+	if err != nil {
+		return objs, err
+	}
+
+	i.cache.store(path, resp, objs)
+	return objs, nil
+}
+
+// decode decodes the objects in body, which is a single object when single is true and a list of objects otherwise
+func decode(interactor ApiType, body io.Reader, single bool) ([]ApiType, error) {
 	// If single is true, only a single instance is expected to be returned
 	if single {
-		bts, err := ioutil.ReadAll(resp.Body)
+		bts, err := ioutil.ReadAll(body)
 		if err != nil {
 			return nil, fmt.Errorf("could not read response body; %w", err)
 		}
@@ -581,7 +1095,7 @@ func (i inter) retrieve(interactor ApiType, path string, single bool) ([]ApiType
 	}
 
 	// Some json should be returned, construct a decoder
-	decoder := json.NewDecoder(resp.Body)
+	decoder := json.NewDecoder(body)
 
 	// We read everything into a slice of
 	var temp []json.RawMessage
@@ -604,63 +1118,204 @@ func (i inter) retrieve(interactor ApiType, path string, single bool) ([]ApiType
 	return ret, nil
 }
 
-func (i inter) post(interactor ApiType, encodableBody Submittable) (ApiType, error) {
-	var buf = new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(encodableBody)
+// get performs a GET request for path with the additional headers in header, the request is cancelled when ctx is
+// done and retried on temporary failures
+func (i inter) get(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.baseUrl+path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("could not marshal body; %w", err)
+		return nil, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
 	}
 
-	// Post the body
-	resp, err := i.Post(i.baseUrl+i.toPath(interactor), "application/json", buf)
+	return i.do(req, true)
+}
+
+// open retrieves path and returns the body of the response, which should be closed by the caller
+func (i inter) open(ctx context.Context, path string) (io.ReadCloser, error) {
+	resp, err := i.get(ctx, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	if err := responseToError(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// resolve returns the URL of href, which is either absolute or relative to the base URL
+func (i inter) resolve(href string) (string, error) {
+	base, err := url.Parse(i.baseUrl)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return "", fmt.Errorf("invalid href %v; %w", href, err)
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
+
+// ErrUnexpectedMime is returned when a file is served with a different content type than its FileReference specifies
+var ErrUnexpectedMime = errors.New("unexpected content type")
+
+// ErrFileTooLarge is returned when a downloaded file or the files unpacked from a zip archive exceed their limit
+var ErrFileTooLarge = errors.New("file too large")
+
+var (
+	// MaxDownloadSize is the maximum size in bytes of a file retrieved by Download
+	MaxDownloadSize int64 = 256 << 20
+	// MaxUnzippedSize is the maximum total size in bytes of the files unpacked from a zip archive, such as the files
+	// of a submission
+	MaxUnzippedSize int64 = 256 << 20
+)
+
+// Open retrieves the file ref refers to and returns its contents, which should be closed by the caller. The href of
+// ref is either absolute or relative to the base URL.
+func (i inter) Open(ref FileReference) (io.ReadCloser, error) {
+	return i.OpenContext(context.Background(), ref)
+}
+
+func (i inter) OpenContext(ctx context.Context, ref FileReference) (io.ReadCloser, error) {
+	if ref.Href == "" {
+		return nil, fmt.Errorf("file reference has no href")
+	}
+
+	u, err := i.resolve(ref.Href)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := i.do(req, true)
+	if err != nil {
+		return nil, err
+	}
 
 	if err := responseToError(resp); err != nil {
+		resp.Body.Close()
 		return nil, err
 	}
 
-	bts, err := ioutil.ReadAll(resp.Body)
+	if err := checkMime(ref.Mime, resp.Header.Get("Content-Type")); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("could not open %v; %w", ref.Href, err)
+	}
+
+	return resp.Body, nil
+}
+
+// Download retrieves the file ref refers to, see Open
+func (i inter) Download(ref FileReference) ([]byte, error) {
+	return i.DownloadContext(context.Background(), ref)
+}
+
+func (i inter) DownloadContext(ctx context.Context, ref FileReference) ([]byte, error) {
+	body, err := i.OpenContext(ctx, ref)
 	if err != nil {
-		return nil, fmt.Errorf("could not read response body; %w", err)
+		return nil, err
 	}
+	defer body.Close()
 
-	return interactor.FromJSON(bts)
+	data, err := readAll(body, MaxDownloadSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %v; %w", ref.Href, err)
+	}
+
+	return data, nil
+}
+
+// readAll reads r until EOF, returning ErrFileTooLarge when r contains more than limit bytes
+func readAll(r io.Reader, limit int64) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("more than %d bytes; %w", limit, ErrFileTooLarge)
+	}
+
+	return data, nil
+}
+
+// checkMime returns an error wrapping ErrUnexpectedMime when contentType does not match expected. Nothing is checked
+// when either is unknown, servers that do not know the type of a file use application/octet-stream.
+func checkMime(expected, contentType string) error {
+	if expected == "" || contentType == "" {
+		return nil
+	}
+
+	actual, _, err := mime.ParseMediaType(contentType)
+	if err != nil || actual == "application/octet-stream" {
+		return nil
+	}
+
+	if want, _, err := mime.ParseMediaType(expected); err == nil && !strings.EqualFold(want, actual) {
+		return fmt.Errorf("%w: expected %v, got %v", ErrUnexpectedMime, want, actual)
+	}
+
+	return nil
+}
+
+func (i inter) post(ctx context.Context, interactor ApiType, encodableBody Submittable) (ApiType, error) {
+	return i.send(ctx, http.MethodPost, i.toPath(interactor), interactor, encodableBody)
 }
 
-func responseToError(r *http.Response) error {
-	var statusErr error
-	switch r.StatusCode {
-	case http.StatusOK:
-		return statusErr
-	case http.StatusBadRequest:
-		statusErr = errBadRequest
-	case http.StatusUnauthorized:
-		statusErr = errUnauthorized
-	case http.StatusForbidden:
-		statusErr = errForbidden
-	case http.StatusNotFound:
-		statusErr = errNotFound
-	case http.StatusConflict:
-		statusErr = errConflict
-	default:
-		statusErr = fmt.Errorf("invalid statuscode received: %d", r.StatusCode)
+// put creates the object with the given id, which is chosen by the client
+func (i inter) put(ctx context.Context, interactor ApiType, id string, encodableBody Submittable) (ApiType, error) {
+	return i.send(ctx, http.MethodPut, i.toPath(interactor)+"/"+url.PathEscape(id), interactor, encodableBody)
+}
+
+func (i inter) send(ctx context.Context, method, path string, interactor ApiType, encodableBody Submittable) (ApiType, error) {
+	var buf = new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(encodableBody)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal body; %w", err)
 	}
 
-	// Read the contents
-	bts, err := ioutil.ReadAll(r.Body)
+	// Send the body
+	req, err := http.NewRequestWithContext(ctx, method, i.baseUrl+path, buf)
 	if err != nil {
-		return fmt.Errorf("API error (%v), could not read response body: %w", statusErr, err)
+		return nil, err
+	}
+
+	// Sending an object with an id chosen by the client is idempotent, such that it can safely be retried
+	var withId struct {
+		Id string `json:"id"`
 	}
+	_ = json.Unmarshal(buf.Bytes(), &withId)
 
-	var e Error
-	err = json.Unmarshal(bts, &e)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := i.do(req, method == http.MethodPut || withId.Id != "")
 	if err != nil {
-		return fmt.Errorf("API error (%v), couldn't parse details: %w", statusErr, err)
+		return nil, err
 	}
 
-	return fmt.Errorf("%s (error code %d)", e.Message, e.Code)
+	defer resp.Body.Close()
+
+	if err := responseToError(resp); err != nil {
+		return nil, err
+	}
+
+	// The posted object changes the list of objects, which should therefore not be served from the cache
+	i.cache.Invalidate(i.toPath(interactor))
+
+	bts, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response body; %w", err)
+	}
+
+	return interactor.FromJSON(bts)
 }
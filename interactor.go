@@ -1,113 +1,185 @@
 package interactor
 
 import (
+	"context"
 	"crypto/tls"
-	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
 type (
+	// ContestsApi is used to interact with the contests of a CCS. Every method has a variant suffixed with Context which
+	// aborts the request, including reading the response, when the context is done.
 	ContestsApi interface {
 		Contests() ([]Contest, error)
+		ContestsContext(ctx context.Context) ([]Contest, error)
 		ContestById(contestId string) (Contest, error)
+		ContestByIdContext(ctx context.Context, contestId string) (Contest, error)
 		ToContest(cid string) (ContestApi, error)
+		ToContestContext(ctx context.Context, cid string) (ContestApi, error)
+
+		// Info returns information about the API, such as the version of the specification it implements. It is only
+		// retrieved once per interactor.
+		Info() (ApiInfo, error)
+		InfoContext(ctx context.Context) (ApiInfo, error)
+
+		// Open and Download retrieve the file a FileReference refers to, such as a logo or a problem statement
+		Open(ref FileReference) (io.ReadCloser, error)
+		OpenContext(ctx context.Context, ref FileReference) (io.ReadCloser, error)
+		Download(ref FileReference) ([]byte, error)
+		DownloadContext(ctx context.Context, ref FileReference) ([]byte, error)
 	}
 
+	// ContestApi is used to interact with a single contest of a CCS
 	ContestApi interface {
 		ContestsApi
 
 		Contest() (Contest, error)
+		ContestContext(ctx context.Context) (Contest, error)
 
 		State() (State, error)
+		StateContext(ctx context.Context) (State, error)
+
+		// Access returns the capabilities of the account and the endpoints it can see, since 2023-06
+		Access() (Access, error)
+		AccessContext(ctx context.Context) (Access, error)
 
 		JudgementTypes() ([]JudgementType, error)
+		JudgementTypesContext(ctx context.Context) ([]JudgementType, error)
 		JudgementTypeById(judgementTypeId string) (JudgementType, error)
+		JudgementTypeByIdContext(ctx context.Context, judgementTypeId string) (JudgementType, error)
 
 		Languages() ([]Language, error)
+		LanguagesContext(ctx context.Context) ([]Language, error)
 		LanguageById(languageId string) (Language, error)
+		LanguageByIdContext(ctx context.Context, languageId string) (Language, error)
 
 		Problems() ([]Problem, error)
+		ProblemsContext(ctx context.Context) ([]Problem, error)
 		ProblemById(problemId string) (Problem, error)
+		ProblemByIdContext(ctx context.Context, problemId string) (Problem, error)
 
 		Groups() ([]Group, error)
+		GroupsContext(ctx context.Context) ([]Group, error)
 		GroupById(groupId string) (Group, error)
+		GroupByIdContext(ctx context.Context, groupId string) (Group, error)
 
 		Organizations() ([]Organization, error)
+		OrganizationsContext(ctx context.Context) ([]Organization, error)
 		OrganizationById(organizationId string) (Organization, error)
+		OrganizationByIdContext(ctx context.Context, organizationId string) (Organization, error)
 
 		Teams() ([]Team, error)
+		TeamsContext(ctx context.Context) ([]Team, error)
 		TeamById(teamId string) (Team, error)
+		TeamByIdContext(ctx context.Context, teamId string) (Team, error)
 
 		Persons() ([]Person, error)
+		PersonsContext(ctx context.Context) ([]Person, error)
 		PersonById(personId string) (Person, error)
+		PersonByIdContext(ctx context.Context, personId string) (Person, error)
 
 		Accounts() ([]Account, error)
+		AccountsContext(ctx context.Context) ([]Account, error)
 		AccountById(accountId string) (Account, error)
+		AccountByIdContext(ctx context.Context, accountId string) (Account, error)
 		Account() (Account, error)
+		AccountContext(ctx context.Context) (Account, error)
 
 		Submissions() ([]Submission, error)
+		SubmissionsContext(ctx context.Context) ([]Submission, error)
 		SubmissionById(submissionId string) (Submission, error)
+		SubmissionByIdContext(ctx context.Context, submissionId string) (Submission, error)
+		SubmissionFiles(submission Submission) (LocalFileReference, error)
+		SubmissionFilesContext(ctx context.Context, submission Submission) (LocalFileReference, error)
 
 		Judgements() ([]Judgement, error)
+		JudgementsContext(ctx context.Context) ([]Judgement, error)
 		JudgementById(judgementId string) (Judgement, error)
+		JudgementByIdContext(ctx context.Context, judgementId string) (Judgement, error)
+
+		Runs() ([]Run, error)
+		RunsContext(ctx context.Context) ([]Run, error)
+		RunById(runId string) (Run, error)
+		RunByIdContext(ctx context.Context, runId string) (Run, error)
+		JudgementRuns(judgementId string) ([]Run, error)
+		JudgementRunsContext(ctx context.Context, judgementId string) ([]Run, error)
 
 		Clarifications() ([]Clarification, error)
+		ClarificationsContext(ctx context.Context) ([]Clarification, error)
 		ClarificationById(clarificationId string) (Clarification, error)
+		ClarificationByIdContext(ctx context.Context, clarificationId string) (Clarification, error)
+
+		Commentary() ([]Commentary, error)
+		CommentaryContext(ctx context.Context) ([]Commentary, error)
+		CommentaryById(commentaryId string) (Commentary, error)
+		CommentaryByIdContext(ctx context.Context, commentaryId string) (Commentary, error)
+
+		Awards() ([]Award, error)
+		AwardsContext(ctx context.Context) ([]Award, error)
+		AwardById(awardId string) (Award, error)
+		AwardByIdContext(ctx context.Context, awardId string) (Award, error)
 
 		Scoreboard() (Scoreboard, error)
+		ScoreboardContext(ctx context.Context) (Scoreboard, error)
+
+		EventFeed(stream bool) (*EventReader, error)
+		EventFeedContext(ctx context.Context, stream bool) (*EventReader, error)
+		EventFeedSince(since EventPosition, stream bool) (*EventReader, error)
+		EventFeedSinceContext(ctx context.Context, since EventPosition, stream bool) (*EventReader, error)
+		Subscribe(since EventPosition) *Subscription
+		SubscribeContext(ctx context.Context, since EventPosition) *Subscription
 
 		Submit(submittable Submittable) (ApiType, error)
+		SubmitContext(ctx context.Context, submittable Submittable) (ApiType, error)
 		PostClarification(problemId, text string) (Clarification, error)
+		PostClarificationContext(ctx context.Context, problemId, text string) (Clarification, error)
+		PostJuryClarification(clarification Clarification) (Clarification, error)
+		PostJuryClarificationContext(ctx context.Context, clarification Clarification) (Clarification, error)
 		PostSubmission(problemId, languageId, entrypoint string, files LocalFileReference) (Submission, error)
+		PostSubmissionContext(ctx context.Context, problemId, languageId, entrypoint string, files LocalFileReference) (Submission, error)
+		PostProxySubmission(submission Submission) (Submission, error)
+		PostProxySubmissionContext(ctx context.Context, submission Submission) (Submission, error)
 
 		GetObject(interactor ApiType, id string) (ApiType, error)
+		GetObjectContext(ctx context.Context, interactor ApiType, id string) (ApiType, error)
 		GetObjects(interactor ApiType) ([]ApiType, error)
+		GetObjectsContext(ctx context.Context, interactor ApiType) ([]ApiType, error)
 	}
 
 	inter struct {
 		http.Client
 		contestId string
-		username  string
-		password  string
 		baseUrl   string
+		retry     RetryPolicy
+		cache     *ResponseCache
+		info      *infoCache
 	}
-
-	// Implementation of the http.RoundTripper interface, used for always adding basic-auth
-	basicAuthTransport struct {
-		username, password string
-
-		T http.RoundTripper
-	}
-)
-
-var (
-	errBadRequest   = errors.New("bad request")            // 400
-	errUnauthorized = errors.New("request not authorized") // 401
-	errForbidden    = errors.New("forbidden")              // 403
-	errNotFound     = errors.New("object not found")       // 404
-	errConflict     = errors.New("conflict")               // 409
 )
 
-// RoundTrip adds the basic auth headers
-func (b basicAuthTransport) RoundTrip(request *http.Request) (*http.Response, error) {
-	if b.username != "" && b.password != "" {
-		request.SetBasicAuth(b.username, b.password)
-	}
+// ContestInteractor constructs a ContestApi for the contest with id contestId. When username is not empty the
+// credentials are used for basic-auth, when insecure is true the certificate of the server is not verified.
+func ContestInteractor(baseUrl, username, password, contestId string, insecure bool) (ContestApi, error) {
+	return NewContestInteractor(baseUrl, contestId, WithBasicAuth(username, password), WithInsecure(insecure))
+}
 
-	return b.T.RoundTrip(request)
+// ContestsInteractor constructs a ContestsApi, see ContestInteractor for the meaning of the arguments
+func ContestsInteractor(baseUrl, username, password string, insecure bool) (ContestsApi, error) {
+	return NewContestsInteractor(baseUrl, WithBasicAuth(username, password), WithInsecure(insecure))
 }
 
-func ContestInteractor(baseUrl, username, password, contestId string, insecure bool) (ContestApi, error) {
-	i := &inter{
-		baseUrl:   strings.TrimRight(baseUrl, "/") + "/",
-		username:  username,
-		password:  password,
-		contestId: contestId,
-		Client:    buildClient(username, password, insecure),
+// NewContestInteractor constructs a ContestApi for the contest with id contestId, configured by opts. An error is
+// returned when the contest can not be retrieved.
+func NewContestInteractor(baseUrl, contestId string, opts ...Option) (ContestApi, error) {
+	i, err := newInter(baseUrl, opts)
+	if err != nil {
+		return nil, err
 	}
 
+	i.contestId = contestId
 	if _, err := i.ContestById(contestId); err != nil {
 		// If the contest cannot be found, ensure the interactor cannot be used
 		return nil, fmt.Errorf("could not find contest; %w", err)
@@ -116,40 +188,95 @@ func ContestInteractor(baseUrl, username, password, contestId string, insecure b
 	return i, nil
 }
 
-func ContestsInteractor(baseUrl, username, password string, insecure bool) (ContestsApi, error) {
+// NewContestsInteractor constructs a ContestsApi configured by opts. No requests are made.
+func NewContestsInteractor(baseUrl string, opts ...Option) (ContestsApi, error) {
+	i, err := newInter(baseUrl, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func newInter(baseUrl string, opts []Option) (*inter, error) {
+	o, err := buildOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	baseUrl = strings.TrimRight(baseUrl, "/") + "/"
+	base, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url; %w", err)
+	}
+
+	client, err := buildClient(o, base)
+	if err != nil {
+		return nil, err
+	}
+
 	return &inter{
-		baseUrl:  strings.TrimRight(baseUrl, "/") + "/",
-		username: username,
-		password: password,
-		Client:   buildClient(username, password, insecure),
+		baseUrl: baseUrl,
+		Client:  client,
+		retry:   o.retry,
+		cache:   o.cache,
+		info:    new(infoCache),
 	}, nil
 }
 
 // ToContest "upgrades" a ContestsApi to a ContestApi for a specific contest. When called from a ContestApi it can be
 // used to change the current contest associated with that ContestApi.
 func (i *inter) ToContest(cid string) (ContestApi, error) {
+	return i.ToContestContext(context.Background(), cid)
+}
+
+func (i *inter) ToContestContext(ctx context.Context, cid string) (ContestApi, error) {
 	i.contestId = cid
 
-	if _, err := i.ContestById(cid); err != nil {
+	if _, err := i.ContestByIdContext(ctx, cid); err != nil {
 		return nil, fmt.Errorf("could not find contest; %w", err)
 	}
 
 	return i, nil
 }
 
-func buildClient(username, password string, insecure bool) http.Client {
-	// Create a transport for (possibly) insecure communication and adding of basic-auth headers
-	transport := http.DefaultTransport.(*http.Transport)
-	// if insecure {
-	// Check and reuse existing tls config
-	if transport.TLSClientConfig == nil {
-		transport.TLSClientConfig = new(tls.Config)
+// buildClient constructs the client used by a single interactor. The transport is never shared with other clients,
+// such that its TLS and proxy settings only apply to this interactor. Only requests to base are authenticated.
+func buildClient(o options, base *url.URL) (http.Client, error) {
+	var client http.Client
+	if o.client != nil {
+		client = *o.client
+	}
+
+	transport := o.transport
+	if transport == nil {
+		transport = client.Transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
 	}
 
-	transport.TLSClientConfig.InsecureSkipVerify = insecure
-	// }
+	if t, ok := transport.(*http.Transport); ok {
+		t = t.Clone()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = new(tls.Config)
+		}
 
-	return http.Client{
-		Transport: basicAuthTransport{username, password, transport},
+		o.configureTLS(t.TLSClientConfig)
+		if o.proxy != nil {
+			t.Proxy = o.proxy
+		}
+
+		transport = t
+	} else if o.hasTLS() || o.proxy != nil {
+		return client, fmt.Errorf("can not configure TLS or proxy settings of a %T", transport)
+	}
+
+	if o.timeout != 0 {
+		transport = timeoutTransport{o.timeout, transport}
 	}
+
+	// Authenticate every request to the API
+	client.Transport = authTransport{o.auth, base, transport}
+	return client, nil
 }
@@ -1,15 +1,19 @@
 package interactor
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -24,7 +28,7 @@ var (
 	testPass     = envFallback("TEST_PASS", "admin")
 	testTeamUser = envFallback("TEST_TEAM_USER", "team")
 	testTeamPass = envFallback("TEST_TEAM_PASS", "team")
-	testBase     = envFallback("TEST_BASE", "https://www.domjudge.org/demoweb/api")
+	testBase     = envFallback("TEST_BASE", "")
 	testContest  = envFallback("TEST_CONTEST", "nwerc18")
 	testProblem  = envFallback("TEST_PROBLEM", "accesspoints")
 
@@ -35,6 +39,20 @@ var (
 	_ ContestsApi = new(inter)
 )
 
+// TestMain runs the tests against a MockServer, unless TEST_BASE is set to the url of a real server
+func TestMain(m *testing.M) {
+	if testBase == "" {
+		server := NewMockServer(DefaultMockData())
+		testBase = server.URL
+
+		code := m.Run()
+		server.Close()
+		os.Exit(code)
+	}
+
+	os.Exit(m.Run())
+}
+
 func envFallback(k, fb string) string {
 	if v := os.Getenv(k); v != "" {
 		return v
@@ -242,6 +260,176 @@ func TestJudgementRetrieval(t *testing.T) {
 	})
 }
 
+func TestRunRetrieval(t *testing.T) {
+	api := interactor(t)
+
+	var rId, jId string
+	t.Run("all-runs", func(t *testing.T) {
+		runs, err := api.Runs()
+		assert.Nil(t, err)
+		assert.NotNil(t, runs)
+
+		for _, r := range runs {
+			if r.Id != "" {
+				rId, jId = r.Id, r.JudgementId
+				return
+			}
+		}
+	})
+
+	t.Run("single-run", func(t *testing.T) {
+		if rId == "" {
+			t.Skip("no runs could be found, retrieving single run cannot be tested")
+		}
+
+		r, err := api.RunById(rId)
+		assert.Nil(t, err)
+		assert.EqualValues(t, rId, r.Id)
+	})
+
+	t.Run("judgement-runs", func(t *testing.T) {
+		if jId == "" {
+			t.Skip("no runs could be found, retrieving runs of a judgement cannot be tested")
+		}
+
+		runs, err := api.JudgementRuns(jId)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, runs)
+		for k, r := range runs {
+			assert.EqualValues(t, jId, r.JudgementId)
+			if k > 0 {
+				assert.True(t, runs[k-1].Ordinal <= r.Ordinal)
+			}
+		}
+	})
+}
+
+func TestJudgementRunsFilter(t *testing.T) {
+	var reject int32
+	queries := make(chan string, 10)
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/runs":
+			queries <- r.URL.RawQuery
+			if r.URL.Query().Get("judgement_id") != "" && atomic.LoadInt32(&reject) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			// The filter is ignored, runs of other judgements are returned as well
+			_, _ = w.Write([]byte(`[{"id":"r2","judgement_id":"j1","ordinal":2},{"id":"r3","judgement_id":"j2","ordinal":1},{"id":"r1","judgement_id":"j1","ordinal":1}]`))
+		}
+	}))
+	defer ser.Close()
+
+	api, err := NewContestInteractor(ser.URL, "test")
+	assert.Nil(t, err)
+
+	t.Run("filtered", func(t *testing.T) {
+		runs, err := api.JudgementRuns("j1")
+		assert.Nil(t, err)
+		if assert.Len(t, runs, 2) {
+			assert.EqualValues(t, "r1", runs[0].Id)
+			assert.EqualValues(t, "r2", runs[1].Id)
+		}
+		assert.EqualValues(t, "judgement_id=j1", <-queries)
+		assert.Len(t, queries, 0)
+	})
+
+	t.Run("rejected-filter", func(t *testing.T) {
+		atomic.StoreInt32(&reject, 1)
+		runs, err := api.JudgementRuns("j2")
+		assert.Nil(t, err)
+		if assert.Len(t, runs, 1) {
+			assert.EqualValues(t, "r3", runs[0].Id)
+		}
+		assert.EqualValues(t, "judgement_id=j2", <-queries)
+		assert.EqualValues(t, "", <-queries)
+	})
+}
+
+func TestCommentaryRetrieval(t *testing.T) {
+	api := interactor(t)
+
+	var cId string
+	t.Run("all-commentary", func(t *testing.T) {
+		commentary, err := api.Commentary()
+		assert.Nil(t, err)
+		assert.NotNil(t, commentary)
+
+		for _, c := range commentary {
+			if c.Id != "" {
+				cId = c.Id
+				return
+			}
+		}
+	})
+
+	t.Run("single-commentary", func(t *testing.T) {
+		if cId == "" {
+			t.Skip("no commentary could be found, retrieving single commentary cannot be tested")
+		}
+
+		c, err := api.CommentaryById(cId)
+		assert.Nil(t, err)
+		assert.EqualValues(t, cId, c.Id)
+	})
+}
+
+func TestSendCommentary(t *testing.T) {
+	t.Run("unauthorized", func(t *testing.T) {
+		api := teamInteractor(t)
+
+		obj, err := api.Submit(Commentary{Message: "testing commentary"})
+		assert.Nil(t, obj)
+		assert.NotNil(t, err)
+
+		t.Logf("Sent commentary, got error: '%v'", err)
+	})
+
+	t.Run("authorized", func(t *testing.T) {
+		api := interactor(t)
+
+		obj, err := api.Submit(Commentary{Message: "testing commentary", ProblemIds: []string{testProblem}})
+		assert.Nil(t, err)
+		if assert.IsType(t, Commentary{}, obj) {
+			assert.NotEmpty(t, obj.(Commentary).Id)
+		}
+
+		t.Logf("Sent commentary, got: '%v'", obj)
+	})
+}
+
+func TestAwardRetrieval(t *testing.T) {
+	api := interactor(t)
+
+	var aId string
+	t.Run("all-awards", func(t *testing.T) {
+		awards, err := api.Awards()
+		assert.Nil(t, err)
+		assert.NotNil(t, awards)
+
+		for _, a := range awards {
+			if a.Id != "" {
+				aId = a.Id
+				return
+			}
+		}
+	})
+
+	t.Run("single-award", func(t *testing.T) {
+		if aId == "" {
+			t.Skip("no awards could be found, retrieving single award cannot be tested")
+		}
+
+		a, err := api.AwardById(aId)
+		assert.Nil(t, err)
+		assert.EqualValues(t, aId, a.Id)
+	})
+}
+
 func TestGroupRetrieval(t *testing.T) {
 	api := interactor(t)
 
@@ -446,6 +634,44 @@ func TestSendClarification(t *testing.T) {
 	})
 }
 
+func TestSendJuryClarification(t *testing.T) {
+	question := Clarification{Id: "c1", FromTeamId: "1", ProblemId: testProblem}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		api := teamInteractor(t)
+
+		clar, err := api.PostJuryClarification(ClarificationReply(question, "testing reply", false))
+		assert.Empty(t, clar.Id)
+		assert.NotNil(t, err)
+
+		t.Logf("Sent jury clarification, got error: '%v'", err)
+	})
+
+	t.Run("reply", func(t *testing.T) {
+		api := interactor(t)
+
+		clar, err := api.PostJuryClarification(ClarificationReply(question, "testing reply", false))
+		assert.Nil(t, err)
+		assert.NotEmpty(t, clar.Id)
+		assert.EqualValues(t, "c1", clar.ReplyToId)
+		assert.EqualValues(t, "1", clar.ToTeamId)
+
+		t.Logf("Sent jury clarification, got id: '%v'", clar.Id)
+	})
+
+	t.Run("broadcast-with-id", func(t *testing.T) {
+		api := interactor(t)
+
+		id := fmt.Sprintf("jury-%d", time.Now().UnixNano())
+		clar, err := api.PostJuryClarification(Clarification{Id: id, Text: "testing broadcast"})
+		assert.Nil(t, err)
+		assert.EqualValues(t, id, clar.Id)
+		assert.Empty(t, clar.ToTeamId)
+
+		t.Logf("Sent jury clarification, got id: '%v'", clar.Id)
+	})
+}
+
 func TestPostSubmission(t *testing.T) {
 	t.Run("unauthorized", func(t *testing.T) {
 		api := teamInteractor(t)
@@ -499,6 +725,106 @@ func TestPostSubmission(t *testing.T) {
 	})
 }
 
+func TestPostProxySubmission(t *testing.T) {
+	var files LocalFileReference
+	_ = files.FromString("sample.cpp", "int main() { return 0; }")
+	submission := Submission{
+		TeamId:     "2",
+		ProblemId:  testProblem,
+		LanguageId: "cpp",
+		Files:      []FileReference{{Mime: "application/zip", Data: files}},
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		api := teamInteractor(t)
+
+		s, err := api.PostProxySubmission(submission)
+		assert.Empty(t, s.Id)
+		assert.True(t, errors.Is(err, ErrForbidden))
+
+		t.Logf("Sent proxy submission, got error: '%v'", err)
+	})
+
+	t.Run("authorized", func(t *testing.T) {
+		api := interactor(t)
+
+		s, err := api.PostProxySubmission(submission)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, s.Id)
+		assert.EqualValues(t, "2", s.TeamId)
+
+		t.Logf("Sent proxy submission, got id: '%v'", s.Id)
+	})
+
+	t.Run("authorized-with-id", func(t *testing.T) {
+		api := interactor(t)
+
+		withId := submission
+		withId.Id = fmt.Sprintf("proxy-%d", time.Now().UnixNano())
+		at := ApiTime(time.Now().Add(-time.Minute).Truncate(time.Millisecond))
+		withId.Time = &at
+
+		s, err := api.PostProxySubmission(withId)
+		assert.Nil(t, err)
+		assert.EqualValues(t, withId.Id, s.Id)
+		if assert.NotNil(t, s.Time) {
+			assert.True(t, at.Equal(*s.Time))
+		}
+
+		// The id can only be used once
+		_, err = api.PostProxySubmission(withId)
+		assert.NotNil(t, err)
+
+		t.Logf("Sent proxy submission, got id: '%v'", s.Id)
+	})
+}
+
+func TestFileDownload(t *testing.T) {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	api, err := ContestInteractor(server.URL, "", "", "nwerc18", false)
+	assert.Nil(t, err)
+
+	organization, err := api.OrganizationById("uva")
+	assert.Nil(t, err)
+
+	t.Run("best-size", func(t *testing.T) {
+		logo, ok := BestFile(organization.Logo, 100, 100)
+		assert.True(t, ok)
+
+		data, err := api.Download(logo)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "\x89PNG uva 256x256", string(data))
+	})
+
+	t.Run("open", func(t *testing.T) {
+		body, err := api.Open(FileReference{Href: server.URL + "/contests/nwerc18/organizations/uva/logo.64x64.png"})
+		assert.Nil(t, err)
+		defer body.Close()
+
+		data, err := io.ReadAll(body)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "\x89PNG uva 64x64", string(data))
+	})
+
+	t.Run("unexpected-mime", func(t *testing.T) {
+		logo := organization.Logo[0]
+		logo.Mime = "image/svg+xml"
+
+		_, err := api.Download(logo)
+		assert.True(t, errors.Is(err, ErrUnexpectedMime))
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		_, err := api.Download(FileReference{Href: "contests/nwerc18/organizations/uva/banner.png"})
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		_, err = api.Download(FileReference{})
+		assert.NotNil(t, err)
+	})
+}
+
 func TestInvalidCert(t *testing.T) {
 	// This test forces x509 key errors by using a proxy with an invalid certificate
 
@@ -586,3 +912,39 @@ func unwrapsToX509Error(t testLogger, err error) (bool, error) {
 
 	return false, nil
 }
+
+func TestContextCancellation(t *testing.T) {
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		default:
+			// Start sending a list, but never finish it
+			_, _ = w.Write([]byte(`[{"id":"1"},`))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer ser.Close()
+
+	api, err := ContestInteractor(ser.URL, "", "", "test", false)
+	assert.Nil(t, err)
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		submissions, err := api.SubmissionsContext(ctx)
+		assert.Nil(t, submissions)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub := api.SubscribeContext(ctx, EventPosition{})
+
+		time.AfterFunc(50*time.Millisecond, cancel)
+		_, err := sub.Next()
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
@@ -0,0 +1,875 @@
+package interactor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// MockUser is an account of a MockServer. Type is either "admin" or "team", team accounts should have a TeamId.
+	MockUser struct {
+		Username string
+		Password string
+		Type     string
+		TeamId   string
+	}
+
+	// MockContest contains all data of a single contest served by a MockServer
+	MockContest struct {
+		Contest        Contest
+		State          State
+		JudgementTypes []JudgementType
+		Languages      []Language
+		Problems       []Problem
+		Groups         []Group
+		Organizations  []Organization
+		Teams          []Team
+		Persons        []Person
+		Submissions    []Submission
+		Judgements     []Judgement
+		Runs           []Run
+		// Files contains the source files of the submissions, by submission id
+		Files          map[string]LocalFileReference
+		Clarifications []Clarification
+		Awards         []Award
+		Commentary     []Commentary
+		Scoreboard     Scoreboard
+		// Assets are files such as logos and photos, by their path relative to the contest
+		Assets map[string]MockAsset
+	}
+
+	// MockAsset is a file served by a MockServer
+	MockAsset struct {
+		Mime string
+		Data []byte
+	}
+
+	// MockData is the data served by a MockServer
+	MockData struct {
+		Info     ApiInfo
+		Contests []MockContest
+		Users    []MockUser
+	}
+
+	// MockServer is a fake Contest Control System serving MockData over HTTP, to be used in tests. It serves all
+	// endpoints used by ContestApi and accepts posted submissions and clarifications. Requests are authenticated
+	// using basic-auth: anonymous requests can only access public data, team accounts can only access their own
+	// submissions and clarifications and admin accounts can access everything. The event feed is generated from the
+	// current data in the 2022-07 format and always ends after the current events.
+	MockServer struct {
+		*httptest.Server
+
+		mu     sync.Mutex
+		data   MockData
+		nextId int
+	}
+)
+
+const (
+	mockAdmin = "admin"
+	mockTeam  = "team"
+)
+
+// NewMockServer starts a MockServer serving data, which should be closed after use
+func NewMockServer(data MockData) *MockServer {
+	s := &MockServer{data: data, nextId: 1}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// DefaultMockData returns data for a contest with id "nwerc18" which started an hour ago, containing a few teams,
+// submissions and clarifications. The users "admin", "team" and "team2" have their username as password.
+func DefaultMockData() MockData {
+	start := ApiTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	at := func(d time.Duration) *ApiTime {
+		t := start.AddDuration(ApiRelTime(d))
+		return &t
+	}
+
+	return MockData{
+		Info: ApiInfo{
+			Version:    ApiVersion2022_07,
+			VersionURL: "https://ccs-specs.icpc.io/2022-07/contest_api",
+			Name:       "Mock CCS",
+			Provider:   &ApiProvider{Name: "api-interactor"},
+		},
+		Users: []MockUser{
+			{Username: "admin", Password: "admin", Type: mockAdmin},
+			{Username: "team", Password: "team", Type: mockTeam, TeamId: "1"},
+			{Username: "team2", Password: "team2", Type: mockTeam, TeamId: "2"},
+		},
+		Contests: []MockContest{{
+			Contest: Contest{
+				Id:                       "nwerc18",
+				Name:                     "NWERC 2018",
+				FormalName:               "Northwestern Europe Regional Contest 2018",
+				StartTime:                start,
+				Duration:                 ApiRelTime(5 * time.Hour),
+				ScoreboardFreezeDuration: ApiRelTime(time.Hour),
+			},
+			State: State{Started: &start},
+			JudgementTypes: []JudgementType{
+				{Id: "AC", Name: "correct", Solved: true},
+				{Id: "WA", Name: "wrong answer", Penalty: true},
+				{Id: "TLE", Name: "timelimit", Penalty: true},
+				{Id: "RTE", Name: "run error", Penalty: true},
+				{Id: "CE", Name: "compiler error"},
+			},
+			Languages: []Language{
+				{Id: "c", Name: "C", Extensions: []string{"c"}},
+				{Id: "cpp", Name: "C++", Extensions: []string{"cpp", "cc"}},
+				{Id: "java", Name: "Java", EntryPointRequired: true, EntryPointName: "Main class", Extensions: []string{"java"}},
+				{Id: "python3", Name: "Python 3", Extensions: []string{"py"}},
+			},
+			Problems: []Problem{
+				{Id: "accesspoints", Label: "A", Name: "Access Points", Ordinal: 0},
+				{Id: "brexit", Label: "B", Name: "Brexit Negotiations", Ordinal: 1},
+				{Id: "circuitdesign", Label: "C", Name: "Circuit Board Design", Ordinal: 2},
+			},
+			Groups: []Group{
+				{Id: "participants", Name: "Participants", Type: "participants"},
+				{Id: "observers", Name: "Observers", Type: "observers", Hidden: true},
+			},
+			Organizations: []Organization{
+				{Id: "uva", Name: "UvA", FormalName: "University of Amsterdam", Country: "NLD", Logo: []FileReference{
+					{Href: "contests/nwerc18/organizations/uva/logo.64x64.png", Mime: "image/png", Width: 64, Height: 64},
+					{Href: "contests/nwerc18/organizations/uva/logo.256x256.png", Mime: "image/png", Width: 256, Height: 256},
+				}},
+				{Id: "kth", Name: "KTH", FormalName: "KTH Royal Institute of Technology", Country: "SWE"},
+			},
+			Teams: []Team{
+				{Id: "1", Name: "Team One", DisplayName: "Team One", GroupIds: []string{"participants"}, OrganizationId: "uva"},
+				{Id: "2", Name: "Team Two", DisplayName: "Team Two", GroupIds: []string{"participants"}, OrganizationId: "kth"},
+			},
+			Persons: []Person{
+				{Id: "p1", Name: "Alice", Role: "contestant", TeamId: "1"},
+				{Id: "p2", Name: "Bob", Role: "contestant", TeamId: "2"},
+			},
+			Submissions: []Submission{
+				{Id: "s1", Files: mockFiles("nwerc18", "s1"), LanguageId: "cpp", Time: at(10 * time.Minute), ContestTime: ApiRelTime(10 * time.Minute), TeamId: "1", ProblemId: "accesspoints"},
+				{Id: "s2", Files: mockFiles("nwerc18", "s2"), LanguageId: "cpp", Time: at(20 * time.Minute), ContestTime: ApiRelTime(20 * time.Minute), TeamId: "1", ProblemId: "accesspoints"},
+				{Id: "s3", Files: mockFiles("nwerc18", "s3"), LanguageId: "java", Time: at(30 * time.Minute), ContestTime: ApiRelTime(30 * time.Minute), TeamId: "2", ProblemId: "brexit"},
+			},
+			Files: map[string]LocalFileReference{
+				"s1": mockSource("main.cpp", "int main() { return 1; }"),
+				"s2": mockSource("main.cpp", "int main() { return 0; }"),
+				"s3": mockSource("Main.java", "public class Main { public static void main(String[] args) {} }"),
+			},
+			Judgements: []Judgement{
+				{Id: "j1", SubmissionId: "s1", JudgementTypeId: "WA", StartTime: at(10 * time.Minute), StartContestTime: ApiRelTime(10 * time.Minute), EndTime: at(11 * time.Minute), EndContestTime: ApiRelTime(11 * time.Minute)},
+				{Id: "j2", SubmissionId: "s2", JudgementTypeId: "AC", StartTime: at(20 * time.Minute), StartContestTime: ApiRelTime(20 * time.Minute), EndTime: at(21 * time.Minute), EndContestTime: ApiRelTime(21 * time.Minute)},
+				{Id: "j3", SubmissionId: "s3", JudgementTypeId: "AC", StartTime: at(30 * time.Minute), StartContestTime: ApiRelTime(30 * time.Minute), EndTime: at(31 * time.Minute), EndContestTime: ApiRelTime(31 * time.Minute)},
+			},
+			Runs: []Run{
+				{Id: "r1", JudgementId: "j1", Ordinal: 1, JudgementTypeId: "AC", Time: at(10 * time.Minute), ContestTime: ApiRelTime(10 * time.Minute), RunTime: 0.1},
+				{Id: "r2", JudgementId: "j1", Ordinal: 2, JudgementTypeId: "WA", Time: at(11 * time.Minute), ContestTime: ApiRelTime(11 * time.Minute), RunTime: 0.2},
+				{Id: "r3", JudgementId: "j2", Ordinal: 1, JudgementTypeId: "AC", Time: at(20 * time.Minute), ContestTime: ApiRelTime(20 * time.Minute), RunTime: 0.1},
+				{Id: "r4", JudgementId: "j2", Ordinal: 2, JudgementTypeId: "AC", Time: at(21 * time.Minute), ContestTime: ApiRelTime(21 * time.Minute), RunTime: 0.3},
+				{Id: "r5", JudgementId: "j3", Ordinal: 1, JudgementTypeId: "AC", Time: at(31 * time.Minute), ContestTime: ApiRelTime(31 * time.Minute), RunTime: 0.5},
+			},
+			Clarifications: []Clarification{
+				{Id: "c1", FromTeamId: "1", ProblemId: "accesspoints", Text: "Can access points overlap?", Time: at(15 * time.Minute), ContestTime: ApiRelTime(15 * time.Minute)},
+				{Id: "c2", ReplyToId: "c1", ProblemId: "accesspoints", Text: "Yes.", Time: at(16 * time.Minute), ContestTime: ApiRelTime(16 * time.Minute)},
+			},
+			Awards: []Award{
+				{Id: "winner", Citation: "Contest winner", TeamIds: []string{"2"}},
+				{Id: "first-to-solve-accesspoints", Citation: "First to solve problem A", TeamIds: []string{"1"}},
+			},
+			Commentary: []Commentary{
+				{Id: "m1", Message: "#t2 solved #pbrexit", TeamIds: []string{"2"}, ProblemIds: []string{"brexit"}, SubmissionIds: []string{"s3"}, Time: at(31 * time.Minute), ContestTime: ApiRelTime(31 * time.Minute)},
+			},
+			Scoreboard: Scoreboard{
+				Time:        start.AddDuration(ApiRelTime(31 * time.Minute)),
+				ContestTime: ApiRelTime(31 * time.Minute),
+				State:       State{Started: &start},
+				Rows: []Row{
+					{Rank: 1, TeamId: "2", Score: Score{NumSolved: 1, TotalTime: 30}, Problems: []ScoreProblem{
+						{ProblemId: "accesspoints"},
+						{ProblemId: "brexit", NumJudged: 1, Solved: true, Time: 30},
+						{ProblemId: "circuitdesign"},
+					}},
+					{Rank: 2, TeamId: "1", Score: Score{NumSolved: 1, TotalTime: 40}, Problems: []ScoreProblem{
+						{ProblemId: "accesspoints", NumJudged: 2, Solved: true, Time: 20},
+						{ProblemId: "brexit"},
+						{ProblemId: "circuitdesign"},
+					}},
+				},
+			},
+			Assets: map[string]MockAsset{
+				"organizations/uva/logo.64x64.png":   {Mime: "image/png", Data: []byte("\x89PNG uva 64x64")},
+				"organizations/uva/logo.256x256.png": {Mime: "image/png", Data: []byte("\x89PNG uva 256x256")},
+			},
+		}},
+	}
+}
+
+// Data returns a copy of the data currently served, including posted objects
+func (s *MockServer) Data() MockData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := s.data
+	data.Users = append([]MockUser(nil), s.data.Users...)
+	data.Contests = make([]MockContest, len(s.data.Contests))
+	for k, c := range s.data.Contests {
+		data.Contests[k] = c.copy()
+	}
+
+	return data
+}
+
+// copy returns a copy of c that does not share its lists with c, such that it can be read while c is modified
+func (c MockContest) copy() MockContest {
+	c.JudgementTypes = append([]JudgementType(nil), c.JudgementTypes...)
+	c.Languages = append([]Language(nil), c.Languages...)
+	c.Problems = append([]Problem(nil), c.Problems...)
+	c.Groups = append([]Group(nil), c.Groups...)
+	c.Organizations = append([]Organization(nil), c.Organizations...)
+	c.Teams = append([]Team(nil), c.Teams...)
+	c.Persons = append([]Person(nil), c.Persons...)
+	c.Submissions = append([]Submission(nil), c.Submissions...)
+	c.Judgements = append([]Judgement(nil), c.Judgements...)
+	c.Runs = append([]Run(nil), c.Runs...)
+	c.Clarifications = append([]Clarification(nil), c.Clarifications...)
+	c.Awards = append([]Award(nil), c.Awards...)
+	c.Commentary = append([]Commentary(nil), c.Commentary...)
+	c.Scoreboard.Rows = append([]Row(nil), c.Scoreboard.Rows...)
+
+	files := make(map[string]LocalFileReference, len(c.Files))
+	for id, f := range c.Files {
+		files[id] = f
+	}
+	c.Files = files
+
+	assets := make(map[string]MockAsset, len(c.Assets))
+	for path, asset := range c.Assets {
+		assets[path] = asset
+	}
+	c.Assets = assets
+
+	return c
+}
+
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.authenticate(r)
+	if !ok {
+		mockError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] == "" {
+		s.serveGet(w, r, s.data.Info)
+		return
+	}
+
+	if parts[0] != "contests" {
+		mockError(w, http.StatusNotFound, "unknown endpoint")
+		return
+	}
+
+	if len(parts) == 1 {
+		contests := make([]Contest, len(s.data.Contests))
+		for k, c := range s.data.Contests {
+			contests[k] = c.Contest
+		}
+
+		s.serveGet(w, r, contests)
+		return
+	}
+
+	c := s.contest(parts[1])
+	if c == nil {
+		mockError(w, http.StatusNotFound, "contest not found")
+		return
+	}
+
+	if len(parts) == 2 {
+		s.serveGet(w, r, c.Contest)
+		return
+	}
+
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "state":
+			s.serveGet(w, r, c.State)
+			return
+		case "scoreboard":
+			s.serveGet(w, r, c.Scoreboard)
+			return
+		case "access":
+			s.serveGet(w, r, c.access(user))
+			return
+		case "account":
+			if user == nil {
+				mockError(w, http.StatusUnauthorized, "not logged in")
+				return
+			}
+
+			s.serveGet(w, r, user.account())
+			return
+		case "event-feed":
+			s.serveEventFeed(w, r, c, user)
+			return
+		}
+	}
+
+	objs, ok := c.visible(parts[2], user)
+	if !ok {
+		mockError(w, http.StatusNotFound, "unknown endpoint")
+		return
+	}
+
+	if objs == nil {
+		mockError(w, http.StatusForbidden, "not allowed to access "+parts[2])
+		return
+	}
+
+	switch {
+	case len(parts) == 5 && parts[2] == "submissions" && parts[4] == "files":
+		s.serveFiles(w, c, objs, parts[3])
+	case len(parts) > 4 && r.Method == http.MethodGet:
+		asset, ok := c.Assets[strings.Join(parts[2:], "/")]
+		if !ok {
+			mockError(w, http.StatusNotFound, "file not found")
+			return
+		}
+
+		w.Header().Set("Content-Type", asset.Mime)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(asset.Data)
+	case len(parts) == 3 && r.Method == http.MethodPost:
+		s.servePost(w, r, c, parts[2], user, "")
+	case len(parts) == 4 && r.Method == http.MethodPut:
+		s.servePost(w, r, c, parts[2], user, parts[3])
+	case len(parts) == 3 && parts[2] == "runs" && r.URL.Query().Get("judgement_id") != "":
+		runs := []ApiType{}
+		for _, obj := range objs {
+			if obj.(Run).JudgementId == r.URL.Query().Get("judgement_id") {
+				runs = append(runs, obj)
+			}
+		}
+
+		s.serveGet(w, r, runs)
+	case len(parts) == 3:
+		s.serveGet(w, r, objs)
+	case len(parts) == 4:
+		for _, obj := range objs {
+			if mockId(obj) == parts[3] {
+				s.serveGet(w, r, obj)
+				return
+			}
+		}
+
+		mockError(w, http.StatusNotFound, "object not found")
+	default:
+		mockError(w, http.StatusNotFound, "unknown endpoint")
+	}
+}
+
+// authenticate returns the user of the request, nil for anonymous requests. False is returned when the credentials
+// are invalid.
+func (s *MockServer) authenticate(r *http.Request) (*MockUser, bool) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, true
+	}
+
+	for k, u := range s.data.Users {
+		if u.Username == username && u.Password == password {
+			return &s.data.Users[k], true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *MockServer) contest(id string) *MockContest {
+	for k := range s.data.Contests {
+		if s.data.Contests[k].Contest.Id == id {
+			return &s.data.Contests[k]
+		}
+	}
+
+	return nil
+}
+
+func (s *MockServer) serveGet(w http.ResponseWriter, r *http.Request, v interface{}) {
+	if r.Method != http.MethodGet {
+		mockError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	mockJSON(w, http.StatusOK, v)
+}
+
+// servePost creates an object of type typ, id is set when the object is PUT with an id chosen by the client
+func (s *MockServer) servePost(w http.ResponseWriter, r *http.Request, c *MockContest, typ string, user *MockUser, id string) {
+	if user == nil {
+		mockError(w, http.StatusUnauthorized, "not logged in")
+		return
+	}
+
+	if id != "" && user.Type != mockAdmin {
+		mockError(w, http.StatusForbidden, "only admins can choose the id of an object")
+		return
+	}
+
+	now := ApiTime(time.Now())
+	contestTime := ApiRelTime(time.Since(c.Contest.StartTime.Time()))
+
+	switch typ {
+	case "submissions":
+		var req Submission
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			mockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		sub := req
+		if !s.useId(w, c, typ, &sub.Id, id) {
+			return
+		}
+
+		// Only admins may submit on behalf of a team and choose the id and time of the submission
+		if user.Type == mockTeam {
+			sub.TeamId, sub.Id, sub.Time = user.TeamId, "", nil
+		} else if sub.TeamId == "" {
+			mockError(w, http.StatusBadRequest, "team_id is required")
+			return
+		}
+
+		if err := c.validate(sub.ProblemId, sub.LanguageId, sub.TeamId); err != nil {
+			mockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if len(req.Files) == 0 || len(req.Files[0].Data.files) == 0 {
+			mockError(w, http.StatusBadRequest, "files are required")
+			return
+		}
+
+		if sub.Id == "" {
+			sub.Id = s.id()
+		}
+		if sub.Time == nil || sub.Time.Time().IsZero() {
+			sub.Time = &now
+		}
+
+		sub.ContestTime = ApiRelTime(sub.Time.Time().Sub(c.Contest.StartTime.Time()))
+		sub.Files = mockFiles(c.Contest.Id, sub.Id)
+		c.Submissions = append(c.Submissions, sub)
+		if c.Files == nil {
+			c.Files = make(map[string]LocalFileReference)
+		}
+		c.Files[sub.Id] = req.Files[0].Data
+		mockJSON(w, http.StatusOK, sub)
+	case "clarifications":
+		var clar Clarification
+		if err := json.NewDecoder(r.Body).Decode(&clar); err != nil {
+			mockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if !s.useId(w, c, typ, &clar.Id, id) {
+			return
+		}
+
+		if user.Type == mockTeam {
+			if clar.ToTeamId != "" || clar.ReplyToId != "" || (clar.FromTeamId != "" && clar.FromTeamId != user.TeamId) {
+				mockError(w, http.StatusForbidden, "teams can only ask questions")
+				return
+			}
+
+			clar = Clarification{FromTeamId: user.TeamId, ProblemId: clar.ProblemId, Text: clar.Text}
+		}
+
+		if clar.ProblemId != "" {
+			if err := c.validate(clar.ProblemId, "", ""); err != nil {
+				mockError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		if clar.ReplyToId != "" && !c.has(typ, clar.ReplyToId) {
+			mockError(w, http.StatusBadRequest, fmt.Sprintf("clarification %q not found", clar.ReplyToId))
+			return
+		}
+
+		for _, teamId := range []string{clar.FromTeamId, clar.ToTeamId} {
+			if teamId != "" && !c.has("teams", teamId) {
+				mockError(w, http.StatusBadRequest, fmt.Sprintf("team %q not found", teamId))
+				return
+			}
+		}
+
+		if clar.Id == "" {
+			clar.Id = s.id()
+		}
+
+		// Only admins may choose the time of a clarification
+		if clar.Time == nil || clar.Time.Time().IsZero() {
+			clar.Time = &now
+		}
+
+		clar.ContestTime = ApiRelTime(clar.Time.Time().Sub(c.Contest.StartTime.Time()))
+		c.Clarifications = append(c.Clarifications, clar)
+		mockJSON(w, http.StatusOK, clar)
+	case "commentary":
+		if user.Type != mockAdmin {
+			mockError(w, http.StatusForbidden, "only admins can post commentary")
+			return
+		}
+
+		var commentary Commentary
+		if err := json.NewDecoder(r.Body).Decode(&commentary); err != nil {
+			mockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if !s.useId(w, c, typ, &commentary.Id, id) {
+			return
+		}
+
+		if commentary.Id == "" {
+			commentary.Id = s.id()
+		}
+
+		commentary.Time, commentary.ContestTime = &now, contestTime
+		c.Commentary = append(c.Commentary, commentary)
+		mockJSON(w, http.StatusOK, commentary)
+	default:
+		mockError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// serveFiles serves the files of a submission as a zip archive, if the submission is in objs
+func (s *MockServer) serveFiles(w http.ResponseWriter, c *MockContest, objs []ApiType, submissionId string) {
+	files, ok := c.Files[submissionId]
+	if !ok {
+		mockError(w, http.StatusNotFound, "files not found")
+		return
+	}
+
+	for _, obj := range objs {
+		if mockId(obj) == submissionId {
+			data, err := files.Zip()
+			if err != nil {
+				mockError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/zip")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(data)
+			return
+		}
+	}
+
+	mockError(w, http.StatusNotFound, "submission not found")
+}
+
+// mockFiles returns the reference to the files of a submission served by a MockServer
+func mockFiles(contestId, submissionId string) []FileReference {
+	return []FileReference{{Href: "contests/" + contestId + "/submissions/" + submissionId + "/files", Mime: "application/zip"}}
+}
+
+// mockSource returns a single source file
+func mockSource(filename, contents string) LocalFileReference {
+	var files LocalFileReference
+	_ = files.FromString(filename, contents)
+	return files
+}
+
+// useId sets the id of a new object of type typ to the id of the url, which should match the id in the body. False
+// is returned when an error was written, because the ids do not match or the id is already used.
+func (s *MockServer) useId(w http.ResponseWriter, c *MockContest, typ string, bodyId *string, id string) bool {
+	if id != "" {
+		if *bodyId != "" && *bodyId != id {
+			mockError(w, http.StatusBadRequest, "the id in the body does not match the url")
+			return false
+		}
+
+		*bodyId = id
+	}
+
+	if *bodyId != "" && c.has(typ, *bodyId) {
+		mockError(w, http.StatusConflict, fmt.Sprintf("%s with id %q already exists", typ, *bodyId))
+		return false
+	}
+
+	return true
+}
+
+func (s *MockServer) serveEventFeed(w http.ResponseWriter, r *http.Request, c *MockContest, user *MockUser) {
+	if user == nil || user.Type != mockAdmin {
+		mockError(w, http.StatusForbidden, "the event feed is only available to admins")
+		return
+	}
+
+	var objs []ApiType
+	objs = append(objs, c.Contest)
+	for _, typ := range []string{"judgement-types", "languages", "problems", "groups", "organizations", "teams",
+		"persons"} {
+		list, _ := c.visible(typ, user)
+		objs = append(objs, list...)
+	}
+	objs = append(objs, c.State)
+	for _, typ := range []string{"submissions", "judgements", "runs", "clarifications", "awards", "commentary"} {
+		list, _ := c.visible(typ, user)
+		objs = append(objs, list...)
+	}
+
+	// The token of an event is its index, starting at 1
+	since, _ := strconv.Atoi(r.URL.Query().Get("since_token"))
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	for k, obj := range objs {
+		if k+1 <= since {
+			continue
+		}
+
+		typ := obj.Path()
+		if _, ok := obj.(Contest); ok {
+			typ = "contest"
+		}
+
+		data, _ := json.Marshal(obj)
+		id, _ := json.Marshal(mockId(obj))
+		if id == nil || string(id) == `""` {
+			id = []byte("null")
+		}
+
+		_, _ = fmt.Fprintf(w, `{"type":%q,"id":%s,"data":%s,"token":"%d"}`+"\n", typ, id, data, k+1)
+	}
+}
+
+func (s *MockServer) id() string {
+	s.nextId++
+	return fmt.Sprintf("mock-%d", s.nextId-1)
+}
+
+// visible returns the objects of the given type that user can see. False is returned for unknown types, a nil slice
+// when the user can not access the type at all.
+func (c *MockContest) visible(typ string, user *MockUser) ([]ApiType, bool) {
+	admin := user != nil && user.Type == mockAdmin
+	var teamId string
+	if user != nil && user.Type == mockTeam {
+		teamId = user.TeamId
+	}
+
+	objs := []ApiType{}
+	switch typ {
+	case "judgement-types":
+		for _, v := range c.JudgementTypes {
+			objs = append(objs, v)
+		}
+	case "languages":
+		for _, v := range c.Languages {
+			objs = append(objs, v)
+		}
+	case "problems":
+		for _, v := range c.Problems {
+			objs = append(objs, v)
+		}
+	case "groups":
+		for _, v := range c.Groups {
+			objs = append(objs, v)
+		}
+	case "organizations":
+		for _, v := range c.Organizations {
+			objs = append(objs, v)
+		}
+	case "teams":
+		for _, v := range c.Teams {
+			objs = append(objs, v)
+		}
+	case "persons":
+		for _, v := range c.Persons {
+			objs = append(objs, v)
+		}
+	case "awards":
+		for _, v := range c.Awards {
+			objs = append(objs, v)
+		}
+	case "commentary":
+		for _, v := range c.Commentary {
+			objs = append(objs, v)
+		}
+	case "accounts":
+		if !admin {
+			return nil, true
+		}
+
+		// Accounts are derived from the users, which are not part of the contest
+		return nil, false
+	case "submissions":
+		if user == nil {
+			return nil, true
+		}
+
+		for _, v := range c.Submissions {
+			if admin || v.TeamId == teamId {
+				objs = append(objs, v)
+			}
+		}
+	case "judgements":
+		if user == nil {
+			return nil, true
+		}
+
+		for _, v := range c.Judgements {
+			if admin || c.submissionTeam(v.SubmissionId) == teamId {
+				objs = append(objs, v)
+			}
+		}
+	case "runs":
+		if user == nil {
+			return nil, true
+		}
+
+		for _, v := range c.Runs {
+			if admin || c.judgementTeam(v.JudgementId) == teamId {
+				objs = append(objs, v)
+			}
+		}
+	case "clarifications":
+		if user == nil {
+			return nil, true
+		}
+
+		for _, v := range c.Clarifications {
+			// Teams see their own questions, replies to them and broadcasts
+			if admin || v.FromTeamId == teamId || v.ToTeamId == teamId || (v.FromTeamId == "" && v.ToTeamId == "") {
+				objs = append(objs, v)
+			}
+		}
+	default:
+		return nil, false
+	}
+
+	return objs, true
+}
+
+// validate checks that the given problem, language and team exist, empty ids are not checked
+func (c *MockContest) validate(problemId, languageId, teamId string) error {
+	if !c.has("problems", problemId) {
+		return fmt.Errorf("problem %q not found", problemId)
+	}
+	if languageId != "" && !c.has("languages", languageId) {
+		return fmt.Errorf("language %q not found", languageId)
+	}
+	if teamId != "" && !c.has("teams", teamId) {
+		return fmt.Errorf("team %q not found", teamId)
+	}
+
+	return nil
+}
+
+func (c *MockContest) has(typ, id string) bool {
+	objs, _ := c.visible(typ, &MockUser{Type: mockAdmin})
+	for _, obj := range objs {
+		if mockId(obj) == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *MockContest) submissionTeam(submissionId string) string {
+	for _, s := range c.Submissions {
+		if s.Id == submissionId {
+			return s.TeamId
+		}
+	}
+
+	return ""
+}
+
+func (c *MockContest) judgementTeam(judgementId string) string {
+	for _, j := range c.Judgements {
+		if j.Id == judgementId {
+			return c.submissionTeam(j.SubmissionId)
+		}
+	}
+
+	return ""
+}
+
+// access returns the capabilities of user and the endpoints the user can see
+func (c *MockContest) access(user *MockUser) Access {
+	access := Access{Capabilities: []string{}}
+	switch {
+	case user != nil && user.Type == mockAdmin:
+		access.Capabilities = []string{CapabilityContestStart, CapabilityProxySubmit, CapabilityProxyClar,
+			CapabilityAdminSubmit, CapabilityAdminClar}
+	case user != nil:
+		access.Capabilities = []string{CapabilityTeamSubmit, CapabilityTeamClar}
+	}
+
+	endpoints := []ApiType{Contest{}, State{}, JudgementType{}, Language{}, Problem{}, Group{}, Organization{},
+		Team{}, Person{}, Submission{}, Judgement{}, Run{}, Clarification{}, Award{}, Commentary{}, Scoreboard{}}
+	for _, e := range endpoints {
+		if objs, ok := c.visible(e.Path(), user); ok && objs == nil {
+			continue
+		}
+
+		typ := e.Path()
+		if _, ok := e.(Contest); ok {
+			typ = "contest"
+		}
+
+		access.Endpoints = append(access.Endpoints, AccessEndpoint{Type: typ, Properties: mockProperties(e)})
+	}
+
+	return access
+}
+
+func (u MockUser) account() Account {
+	return Account{Id: u.Username, Username: u.Username, Type: u.Type, TeamId: u.TeamId}
+}
+
+// mockProperties returns the names of the JSON properties of obj
+func mockProperties(obj ApiType) []string {
+	var properties []string
+	t := reflect.TypeOf(obj)
+	for k := 0; k < t.NumField(); k++ {
+		if name := strings.Split(t.Field(k).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			properties = append(properties, name)
+		}
+	}
+
+	return properties
+}
+
+// mockId returns the id of obj, empty for singletons
+func mockId(obj ApiType) string {
+	var v struct {
+		Id string `json:"id"`
+	}
+
+	data, _ := json.Marshal(obj)
+	_ = json.Unmarshal(data, &v)
+	return v.Id
+}
+
+func mockJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func mockError(w http.ResponseWriter, status int, message string) {
+	mockJSON(w, status, Error{Code: status, Message: message})
+}
@@ -0,0 +1,121 @@
+package interactor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockServer(t *testing.T) {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	login := func(t *testing.T, username, password string) ContestApi {
+		api, err := ContestInteractor(server.URL, username, password, "nwerc18", false)
+		assert.Nil(t, err)
+		assert.NotNil(t, api)
+		return api
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		api := login(t, "", "")
+
+		problems, err := api.Problems()
+		assert.Nil(t, err)
+		assert.Len(t, problems, 3)
+
+		_, err = api.Submissions()
+		assert.True(t, errors.Is(err, ErrForbidden))
+	})
+
+	t.Run("invalid-credentials", func(t *testing.T) {
+		_, err := ContestInteractor(server.URL, "admin", "wrong", "nwerc18", false)
+		assert.True(t, errors.Is(err, ErrUnauthorized))
+	})
+
+	t.Run("team", func(t *testing.T) {
+		api := login(t, "team2", "team2")
+
+		submissions, err := api.Submissions()
+		assert.Nil(t, err)
+		if assert.Len(t, submissions, 1) {
+			assert.EqualValues(t, "2", submissions[0].TeamId)
+		}
+
+		_, err = api.SubmissionById("s1")
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		// Team 2 can not see the question of team 1, but the reply to it is broadcast
+		clarifications, err := api.Clarifications()
+		assert.Nil(t, err)
+		assert.Len(t, clarifications, 1)
+
+		_, err = api.EventFeed(false)
+		assert.True(t, errors.Is(err, ErrForbidden))
+	})
+
+	t.Run("post-submission", func(t *testing.T) {
+		api := login(t, "team", "team")
+
+		var files LocalFileReference
+		_ = files.FromString("main.py", "print(42)")
+		submission, err := api.PostSubmission("brexit", "python3", "", files)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, submission.Id)
+		assert.EqualValues(t, "1", submission.TeamId)
+
+		_, err = api.PostSubmission("brexit", "cobol", "", files)
+		assert.True(t, errors.Is(err, ErrBadRequest))
+
+		// The admin can see the new submission
+		submission, err = login(t, "admin", "admin").SubmissionById(submission.Id)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "python3", submission.LanguageId)
+	})
+
+	t.Run("event-feed", func(t *testing.T) {
+		api := login(t, "admin", "admin")
+
+		feed, err := api.EventFeed(false)
+		assert.Nil(t, err)
+
+		events := readAllEvents(t, feed)
+		if assert.NotEmpty(t, events) {
+			assert.EqualValues(t, "contest", events[0].Type)
+			assert.EqualValues(t, "1", events[0].Token)
+		}
+
+		feed, err = api.EventFeedSince(EventPosition{Token: "1"}, false)
+		assert.Nil(t, err)
+		assert.Len(t, readAllEvents(t, feed), len(events)-1)
+	})
+
+	t.Run("data", func(t *testing.T) {
+		api := login(t, "team", "team")
+
+		// The data can be read while objects are posted
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			var files LocalFileReference
+			_ = files.FromString("main.py", "print(42)")
+			for k := 0; k < 10; k++ {
+				_, err := api.PostSubmission("brexit", "python3", "", files)
+				assert.Nil(t, err)
+			}
+		}()
+
+		for k := 0; k < 10; k++ {
+			c := server.Data().Contests[0]
+			for _, submission := range c.Submissions {
+				_ = c.Files[submission.Id]
+			}
+		}
+
+		<-done
+		c := server.Data().Contests[0]
+		assert.True(t, len(c.Submissions) >= 10)
+	})
+}
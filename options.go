@@ -0,0 +1,343 @@
+package interactor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	// Option configures an interactor created by NewContestInteractor or NewContestsInteractor
+	Option func(o *options) error
+
+	options struct {
+		auth         Authenticator
+		insecure     bool
+		client       *http.Client
+		transport    http.RoundTripper
+		timeout      time.Duration
+		proxy        func(*http.Request) (*url.URL, error)
+		rootCAs      *x509.CertPool
+		certificates []tls.Certificate
+		pins         [][]byte
+		retry        RetryPolicy
+		cache        *ResponseCache
+	}
+)
+
+// ErrCertificatePinMismatch is returned when none of the certificates of the server match a pinned fingerprint
+var ErrCertificatePinMismatch = errors.New("certificate does not match pinned fingerprint")
+
+// WithBasicAuth adds basic-auth headers with the given credentials to every request, unless username is empty
+func WithBasicAuth(username, password string) Option {
+	return func(o *options) error {
+		o.auth = nil
+		if username != "" {
+			o.auth = BasicAuth{Username: username, Password: password}
+		}
+
+		return nil
+	}
+}
+
+// WithBearerToken adds the given bearer token to every request
+func WithBearerToken(token string) Option {
+	return WithAuthenticator(BearerToken{Token: token})
+}
+
+// WithAuthenticator authenticates every request using auth, replacing any earlier authentication option
+func WithAuthenticator(auth Authenticator) Option {
+	return func(o *options) error {
+		o.auth = auth
+		return nil
+	}
+}
+
+// WithInsecure disables verification of the certificate of the server when insecure is true
+func WithInsecure(insecure bool) Option {
+	return func(o *options) error {
+		o.insecure = insecure
+		return nil
+	}
+}
+
+// WithHTTPClient uses a copy of client for all requests. When the client has a transport it is used as if it was
+// passed to WithTransport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) error {
+		if client == nil {
+			return fmt.Errorf("http client is nil")
+		}
+
+		o.client = client
+		return nil
+	}
+}
+
+// WithTransport uses transport for all requests. When transport is an *http.Transport it is cloned, such that the TLS
+// and proxy settings of the interactor do not affect it. Other transports are used as-is, in which case they can not
+// be combined with options that change the TLS or proxy settings.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) error {
+		if transport == nil {
+			return fmt.Errorf("transport is nil")
+		}
+
+		o.transport = transport
+		return nil
+	}
+}
+
+// WithTimeout sets the time limit of a single request until the headers of the response are received. Reading the
+// body is not limited, such that streaming event feeds are kept open.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) error {
+		o.timeout = timeout
+		return nil
+	}
+}
+
+// timeoutTransport cancels requests for which no response is received within timeout
+type timeoutTransport struct {
+	timeout time.Duration
+	T       http.RoundTripper
+}
+
+func (t timeoutTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(request.Context())
+	timer := time.AfterFunc(t.timeout, cancel)
+
+	resp, err := t.T.RoundTrip(request.WithContext(ctx))
+	expired := !timer.Stop()
+	if err != nil {
+		cancel()
+		if expired && request.Context().Err() == nil {
+			return nil, fmt.Errorf("no response within %v; %w", t.timeout, err)
+		}
+
+		return nil, err
+	}
+
+	if expired {
+		// The response arrived just in time, but its body can no longer be read
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("no response within %v", t.timeout)
+	}
+
+	// The context must stay valid while the body is read
+	resp.Body = cancelBody{resp.Body, cancel}
+	return resp, nil
+}
+
+// cancelBody cancels the context of its request when it is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// WithProxy sends all requests through the proxy at proxyUrl
+func WithProxy(proxyUrl string) Option {
+	return func(o *options) error {
+		u, err := url.Parse(proxyUrl)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url; %w", err)
+		}
+
+		o.proxy = http.ProxyURL(u)
+		return nil
+	}
+}
+
+// WithProxyFunc uses proxy to determine the proxy of every request, see http.Transport.Proxy
+func WithProxyFunc(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(o *options) error {
+		o.proxy = proxy
+		return nil
+	}
+}
+
+// WithCACertificates trusts the PEM encoded certificates in pemCerts, in addition to the system roots
+func WithCACertificates(pemCerts []byte) Option {
+	return func(o *options) error {
+		if o.rootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+
+			o.rootCAs = pool
+		}
+
+		if !o.rootCAs.AppendCertsFromPEM(pemCerts) {
+			return fmt.Errorf("no certificates found in CA bundle")
+		}
+
+		return nil
+	}
+}
+
+// WithCAFile trusts the PEM encoded certificates in the file at path, see WithCACertificates
+func WithCAFile(path string) Option {
+	return func(o *options) error {
+		pemCerts, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not read CA bundle; %w", err)
+		}
+
+		return WithCACertificates(pemCerts)(o)
+	}
+}
+
+// WithClientCertificate presents the PEM encoded certificate and key to the server, for mutual TLS
+func WithClientCertificate(certPEM, keyPEM []byte) Option {
+	return func(o *options) error {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate; %w", err)
+		}
+
+		o.certificates = append(o.certificates, cert)
+		return nil
+	}
+}
+
+// WithClientCertificateFile presents the PEM encoded certificate and key in the given files, see WithClientCertificate
+func WithClientCertificateFile(certFile, keyFile string) Option {
+	return func(o *options) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("invalid client certificate; %w", err)
+		}
+
+		o.certificates = append(o.certificates, cert)
+		return nil
+	}
+}
+
+// WithPinnedCertificate only accepts servers presenting a certificate with the given SHA-256 fingerprint, hex encoded
+// with or without colons. The pin is checked in addition to the normal verification of the certificate, combine it
+// with WithInsecure to only rely on the pin, e.g. for self-signed certificates. Any certificate of a verified chain
+// can be pinned, without verification only the certificate of the server itself. When called multiple times any of
+// the fingerprints is accepted.
+func WithPinnedCertificate(fingerprint string) Option {
+	return func(o *options) error {
+		pin, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+		if err != nil || len(pin) != sha256.Size {
+			return fmt.Errorf("invalid SHA-256 fingerprint: %s", fingerprint)
+		}
+
+		o.pins = append(o.pins, pin)
+		return nil
+	}
+}
+
+// IsTrustError reports whether err is caused by the certificate of the server not being trusted, either because the
+// certificate could not be verified or because it does not match a pinned fingerprint
+func IsTrustError(err error) bool {
+	var (
+		unknownAuthority    x509.UnknownAuthorityError
+		hostname            x509.HostnameError
+		certificateInvalid  x509.CertificateInvalidError
+		systemRoots         x509.SystemRootsError
+		constraintViolation x509.ConstraintViolationError
+		insecureAlgorithm   x509.InsecureAlgorithmError
+	)
+
+	return errors.Is(err, ErrCertificatePinMismatch) ||
+		errors.As(err, &unknownAuthority) ||
+		errors.As(err, &hostname) ||
+		errors.As(err, &certificateInvalid) ||
+		errors.As(err, &systemRoots) ||
+		errors.As(err, &constraintViolation) ||
+		errors.As(err, &insecureAlgorithm)
+}
+
+// hasTLS reports whether any of the TLS settings differ from the default
+func (o options) hasTLS() bool {
+	return o.insecure || o.rootCAs != nil || len(o.certificates) > 0 || len(o.pins) > 0
+}
+
+// configureTLS applies the TLS settings to cfg
+func (o options) configureTLS(cfg *tls.Config) {
+	cfg.InsecureSkipVerify = o.insecure
+	if o.rootCAs != nil {
+		cfg.RootCAs = o.rootCAs
+	}
+
+	if len(o.certificates) > 0 {
+		cfg.Certificates = append(cfg.Certificates, o.certificates...)
+	}
+
+	if len(o.pins) > 0 {
+		pins := o.pins
+		cfg.VerifyConnection = func(state tls.ConnectionState) error {
+			// Any certificate can be appended to the chain sent by the server, so only the leaf is trusted unless the
+			// chain was verified
+			var candidates []*x509.Certificate
+			if len(state.VerifiedChains) > 0 {
+				for _, chain := range state.VerifiedChains {
+					candidates = append(candidates, chain...)
+				}
+			} else if len(state.PeerCertificates) > 0 {
+				candidates = state.PeerCertificates[:1]
+			}
+
+			for _, cert := range candidates {
+				fingerprint := sha256.Sum256(cert.Raw)
+				for _, pin := range pins {
+					if bytes.Equal(fingerprint[:], pin) {
+						return nil
+					}
+				}
+			}
+
+			return ErrCertificatePinMismatch
+		}
+	}
+}
+
+// WithRetryPolicy retries failed GET requests, and POST requests of objects with an id, according to policy. By default
+// requests are not retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) error {
+		o.retry = policy
+		return nil
+	}
+}
+
+// WithCache caches the responses of GET requests in cache, see ResponseCache
+func WithCache(cache *ResponseCache) Option {
+	return func(o *options) error {
+		o.cache = cache
+		return nil
+	}
+}
+
+func buildOptions(opts []Option) (options, error) {
+	var o options
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return o, err
+		}
+	}
+
+	return o, nil
+}
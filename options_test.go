@@ -0,0 +1,245 @@
+package interactor
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripperFunc is a http.RoundTripper that is not an *http.Transport
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestBuildClient(t *testing.T) {
+	t.Run("insecure-not-shared", func(t *testing.T) {
+		insecure, err := buildClient(options{insecure: true}, nil)
+		assert.Nil(t, err)
+		secure, err := buildClient(options{}, nil)
+		assert.Nil(t, err)
+
+		insecureTransport := insecure.Transport.(authTransport).T.(*http.Transport)
+		secureTransport := secure.Transport.(authTransport).T.(*http.Transport)
+		assert.True(t, insecureTransport.TLSClientConfig.InsecureSkipVerify)
+		assert.False(t, secureTransport.TLSClientConfig.InsecureSkipVerify)
+
+		// The default transport must never be changed
+		def := http.DefaultTransport.(*http.Transport)
+		assert.True(t, def.TLSClientConfig == nil || !def.TLSClientConfig.InsecureSkipVerify)
+	})
+
+	t.Run("custom-client", func(t *testing.T) {
+		client, err := buildClient(options{client: &http.Client{Timeout: time.Second}, timeout: time.Minute}, nil)
+		assert.Nil(t, err)
+		assert.EqualValues(t, time.Second, client.Timeout)
+
+		timeout, ok := client.Transport.(authTransport).T.(timeoutTransport)
+		if assert.True(t, ok) {
+			assert.EqualValues(t, time.Minute, timeout.timeout)
+		}
+	})
+
+	t.Run("custom-round-tripper", func(t *testing.T) {
+		rt := roundTripperFunc(http.DefaultTransport.RoundTrip)
+
+		_, err := buildClient(options{transport: rt}, nil)
+		assert.Nil(t, err)
+
+		_, err = buildClient(options{transport: rt, insecure: true}, nil)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestNewContestInteractor(t *testing.T) {
+	var called bool
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.EqualValues(t, "user", user)
+		assert.EqualValues(t, "pass", pass)
+
+		_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+	}))
+	defer ser.Close()
+
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		called = true
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	api, err := NewContestInteractor(ser.URL, "test", WithBasicAuth("user", "pass"), WithTransport(rt),
+		WithTimeout(time.Second))
+	assert.Nil(t, err)
+	assert.NotNil(t, api)
+	assert.True(t, called)
+
+	t.Run("invalid-proxy", func(t *testing.T) {
+		api, err := NewContestInteractor(ser.URL, "test", WithProxy("://invalid"))
+		assert.NotNil(t, err)
+		assert.Nil(t, api)
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/event-feed":
+			// The feed keeps streaming for longer than the timeout
+			w.WriteHeader(http.StatusOK)
+			for k := 0; k < 3; k++ {
+				_, _ = fmt.Fprintf(w, `{"type":"problems","id":"p%d","data":{"id":"p%d"},"token":"%d"}`+"\n", k, k, k)
+				w.(http.Flusher).Flush()
+				time.Sleep(50 * time.Millisecond)
+			}
+		case "/contests/test/state":
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer ser.Close()
+
+	api, err := NewContestInteractor(ser.URL, "test", WithTimeout(100*time.Millisecond))
+	assert.Nil(t, err)
+
+	t.Run("streaming", func(t *testing.T) {
+		feed, err := api.EventFeed(true)
+		assert.Nil(t, err)
+		assert.Len(t, readAllEvents(t, feed), 3)
+	})
+
+	t.Run("slow-response", func(t *testing.T) {
+		_, err := api.State()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestTLSOptions(t *testing.T) {
+	ser := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+	}))
+	defer ser.Close()
+
+	serverPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ser.Certificate().Raw})
+	fingerprint := sha256.Sum256(ser.Certificate().Raw)
+
+	t.Run("untrusted", func(t *testing.T) {
+		_, err := NewContestInteractor(ser.URL, "test")
+		assert.NotNil(t, err)
+		assert.True(t, IsTrustError(err))
+	})
+
+	t.Run("ca-bundle", func(t *testing.T) {
+		api, err := NewContestInteractor(ser.URL, "test", WithCACertificates(serverPEM))
+		assert.Nil(t, err)
+		assert.NotNil(t, api)
+	})
+
+	t.Run("invalid-ca-bundle", func(t *testing.T) {
+		_, err := NewContestInteractor(ser.URL, "test", WithCACertificates([]byte("not a certificate")))
+		assert.NotNil(t, err)
+		assert.False(t, IsTrustError(err))
+	})
+
+	t.Run("pinned", func(t *testing.T) {
+		api, err := NewContestInteractor(ser.URL, "test", WithInsecure(true),
+			WithPinnedCertificate(hex.EncodeToString(fingerprint[:])))
+		assert.Nil(t, err)
+		assert.NotNil(t, api)
+	})
+
+	t.Run("pin-mismatch", func(t *testing.T) {
+		_, err := NewContestInteractor(ser.URL, "test", WithCACertificates(serverPEM),
+			WithPinnedCertificate(strings.Repeat("ab:", sha256.Size-1)+"ab"))
+		assert.NotNil(t, err)
+		assert.True(t, errors.Is(err, ErrCertificatePinMismatch))
+		assert.True(t, IsTrustError(err))
+	})
+
+	t.Run("pinned-not-leaf", func(t *testing.T) {
+		// Another server can append the pinned certificate to the chain of its own certificate
+		certPEM, keyPEM := generateCertificate(t)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		assert.Nil(t, err)
+		cert.Certificate = append(cert.Certificate, ser.Certificate().Raw)
+
+		other := httptest.NewUnstartedServer(ser.Config.Handler)
+		other.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+		other.StartTLS()
+		defer other.Close()
+
+		_, err = NewContestInteractor(other.URL, "test", WithInsecure(true),
+			WithPinnedCertificate(hex.EncodeToString(fingerprint[:])))
+		assert.True(t, errors.Is(err, ErrCertificatePinMismatch))
+	})
+}
+
+func TestClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateCertificate(t)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	assert.Nil(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.Nil(t, err)
+
+	// The server only accepts the generated client certificate
+	ser := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+	}))
+	ser.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: x509.NewCertPool()}
+	ser.TLS.ClientCAs.AddCert(leaf)
+	ser.StartTLS()
+	defer ser.Close()
+
+	t.Run("without", func(t *testing.T) {
+		_, err := NewContestInteractor(ser.URL, "test", WithInsecure(true))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("with", func(t *testing.T) {
+		api, err := NewContestInteractor(ser.URL, "test", WithInsecure(true), WithClientCertificate(certPEM, keyPEM))
+		assert.Nil(t, err)
+		assert.NotNil(t, api)
+	})
+}
+
+// generateCertificate generates a self-signed client certificate and its key, both PEM encoded
+func generateCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
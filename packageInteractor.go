@@ -0,0 +1,940 @@
+package interactor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrReadOnly is returned when writing to a ContestApi that can only be read, such as one backed by a contest package
+var ErrReadOnly = errors.New("contest api is read-only")
+
+// packageInter is a ContestApi reading a contest package written by ExportContest, see PackageInteractor
+type packageInter struct {
+	dir   string
+	model *ContestModel
+	// endpoints contains the paths of the endpoints in the package
+	endpoints  map[string]bool
+	scoreboard *Scoreboard
+	info       *ApiInfo
+}
+
+// PackageInteractor constructs a ContestApi for the contest package in dir, in the layout written by ExportContest.
+// The objects are read from the JSON files in the package, or from event-feed.ndjson when there is no contest.json.
+// Only endpoints that are in the package are available, others return an error wrapping ErrNotFound, as do accounts
+// and access which are never part of a package. When scoreboard.json is missing the scoreboard is computed. All write
+// operations return ErrReadOnly.
+func PackageInteractor(dir string) (ContestApi, error) {
+	p := packageInter{
+		dir:       dir,
+		model:     NewContestModel(),
+		endpoints: make(map[string]bool),
+	}
+
+	var err error
+	if _, statErr := os.Stat(filepath.Join(dir, "contest.json")); statErr == nil {
+		err = p.loadJSON()
+	} else {
+		err = p.loadEventFeed()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load contest package; %w", err)
+	}
+
+	if p.model.Contest().Id == "" {
+		return nil, fmt.Errorf("could not load contest package; contest is missing")
+	}
+
+	var scoreboard Scoreboard
+	if ok, err := readPackageJSON(filepath.Join(dir, "scoreboard.json"), &scoreboard); err != nil {
+		return nil, fmt.Errorf("could not load contest package; %w", err)
+	} else if ok {
+		p.scoreboard = &scoreboard
+	}
+
+	var info ApiInfo
+	if ok, err := readPackageJSON(filepath.Join(dir, "api.json"), &info); err != nil {
+		return nil, fmt.Errorf("could not load contest package; %w", err)
+	} else if ok {
+		p.info = &info
+	}
+
+	return p, nil
+}
+
+// loadJSON loads the objects from the JSON files of the package
+func (p packageInter) loadJSON() error {
+	var contest Contest
+	if _, err := readPackageJSON(filepath.Join(p.dir, "contest.json"), &contest); err != nil {
+		return err
+	}
+	p.model.Update(contest)
+
+	var state State
+	if _, err := readPackageJSON(filepath.Join(p.dir, "state.json"), &state); err != nil {
+		return err
+	}
+	p.model.Update(state)
+
+	for _, e := range packageEndpoints {
+		var list []json.RawMessage
+		ok, err := readPackageJSON(filepath.Join(p.dir, e.typ.Path()+".json"), &list)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		for _, data := range list {
+			obj, err := e.typ.FromJSON(data)
+			if err != nil {
+				return fmt.Errorf("could not decode %s; %w", e.typ.Path(), err)
+			}
+
+			p.model.Update(obj)
+		}
+
+		p.endpoints[e.typ.Path()] = true
+	}
+
+	return nil
+}
+
+// loadEventFeed loads the objects by applying event-feed.ndjson, all endpoints are considered to be in the package
+func (p packageInter) loadEventFeed() error {
+	feed, err := p.EventFeed(false)
+	if err != nil {
+		return err
+	}
+	defer feed.Close()
+
+	if err := p.model.Consume(feed); err != nil {
+		return fmt.Errorf("could not read event feed; %w", err)
+	}
+
+	for _, e := range packageEndpoints {
+		p.endpoints[e.typ.Path()] = true
+	}
+
+	return nil
+}
+
+// readPackageJSON decodes filename into v, returning false when the file does not exist
+func readPackageJSON(filename string, v interface{}) (bool, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("could not decode %v; %w", filepath.Base(filename), err)
+	}
+
+	return true, nil
+}
+
+// endpoint returns an error wrapping ErrNotFound when the endpoint of typ is not in the package
+func (p packageInter) endpoint(typ ApiType) error {
+	if !p.endpoints[typ.Path()] {
+		return fmt.Errorf("contest package does not contain %s; %w", typ.Path(), ErrNotFound)
+	}
+
+	return nil
+}
+
+func notInPackage(typ ApiType, id string) error {
+	return fmt.Errorf("could not find %s %v in contest package; %w", typ.Path(), id, ErrNotFound)
+}
+
+func (p packageInter) Contests() ([]Contest, error) {
+	return p.ContestsContext(context.Background())
+}
+
+func (p packageInter) ContestsContext(ctx context.Context) ([]Contest, error) {
+	return []Contest{p.model.Contest()}, nil
+}
+
+func (p packageInter) ContestById(contestId string) (Contest, error) {
+	return p.ContestByIdContext(context.Background(), contestId)
+}
+
+func (p packageInter) ContestByIdContext(ctx context.Context, contestId string) (Contest, error) {
+	contest := p.model.Contest()
+	if contest.Id != contestId {
+		return Contest{}, notInPackage(contest, contestId)
+	}
+
+	return contest, nil
+}
+
+// ToContest returns the interactor itself, a package only contains a single contest
+func (p packageInter) ToContest(cid string) (ContestApi, error) {
+	return p.ToContestContext(context.Background(), cid)
+}
+
+func (p packageInter) ToContestContext(ctx context.Context, cid string) (ContestApi, error) {
+	if _, err := p.ContestByIdContext(ctx, cid); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Info returns the contents of api.json
+func (p packageInter) Info() (ApiInfo, error) {
+	return p.InfoContext(context.Background())
+}
+
+func (p packageInter) InfoContext(ctx context.Context) (ApiInfo, error) {
+	if p.info == nil {
+		return ApiInfo{}, fmt.Errorf("contest package does not contain api.json; %w", ErrNotFound)
+	}
+
+	return *p.info, nil
+}
+
+// Open opens the file ref refers to, its href should be relative to the root of the package
+func (p packageInter) Open(ref FileReference) (io.ReadCloser, error) {
+	return p.OpenContext(context.Background(), ref)
+}
+
+func (p packageInter) OpenContext(ctx context.Context, ref FileReference) (io.ReadCloser, error) {
+	u, err := url.Parse(ref.Href)
+	if err != nil {
+		return nil, fmt.Errorf("invalid href %v; %w", ref.Href, err)
+	}
+
+	name := filepath.Clean(filepath.FromSlash(u.Path))
+	if ref.Href == "" || u.IsAbs() || u.Host != "" || filepath.IsAbs(name) || name == ".." ||
+		strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("file %v is not part of the contest package; %w", ref.Href, ErrNotFound)
+	}
+
+	f, err := os.Open(filepath.Join(p.dir, name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not find %v in contest package; %w", ref.Href, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (p packageInter) Download(ref FileReference) ([]byte, error) {
+	return p.DownloadContext(context.Background(), ref)
+}
+
+func (p packageInter) DownloadContext(ctx context.Context, ref FileReference) ([]byte, error) {
+	f, err := p.OpenContext(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := readAll(f, MaxDownloadSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %v; %w", ref.Href, err)
+	}
+
+	return data, nil
+}
+
+func (p packageInter) Contest() (Contest, error) {
+	return p.ContestContext(context.Background())
+}
+
+func (p packageInter) ContestContext(ctx context.Context) (Contest, error) {
+	return p.model.Contest(), nil
+}
+
+func (p packageInter) State() (State, error) {
+	return p.StateContext(context.Background())
+}
+
+func (p packageInter) StateContext(ctx context.Context) (State, error) {
+	return p.model.State(), nil
+}
+
+func (p packageInter) Access() (Access, error) {
+	return p.AccessContext(context.Background())
+}
+
+func (p packageInter) AccessContext(ctx context.Context) (Access, error) {
+	return Access{}, fmt.Errorf("contest package does not contain access; %w", ErrNotFound)
+}
+
+func (p packageInter) Accounts() ([]Account, error) {
+	return p.AccountsContext(context.Background())
+}
+
+func (p packageInter) AccountsContext(ctx context.Context) ([]Account, error) {
+	return nil, p.endpoint(Account{})
+}
+
+func (p packageInter) AccountById(accountId string) (Account, error) {
+	return p.AccountByIdContext(context.Background(), accountId)
+}
+
+func (p packageInter) AccountByIdContext(ctx context.Context, accountId string) (Account, error) {
+	return Account{}, notInPackage(Account{}, accountId)
+}
+
+func (p packageInter) Account() (Account, error) {
+	return p.AccountContext(context.Background())
+}
+
+func (p packageInter) AccountContext(ctx context.Context) (Account, error) {
+	return Account{}, fmt.Errorf("contest package has no account; %w", ErrNotFound)
+}
+
+func (p packageInter) JudgementTypes() ([]JudgementType, error) {
+	return p.JudgementTypesContext(context.Background())
+}
+
+func (p packageInter) JudgementTypesContext(ctx context.Context) ([]JudgementType, error) {
+	if err := p.endpoint(JudgementType{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.JudgementTypes(), nil
+}
+
+func (p packageInter) JudgementTypeById(judgementTypeId string) (JudgementType, error) {
+	return p.JudgementTypeByIdContext(context.Background(), judgementTypeId)
+}
+
+func (p packageInter) JudgementTypeByIdContext(ctx context.Context, judgementTypeId string) (JudgementType, error) {
+	v, ok := p.model.JudgementTypeById(judgementTypeId)
+	if !ok {
+		return v, notInPackage(JudgementType{}, judgementTypeId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Languages() ([]Language, error) {
+	return p.LanguagesContext(context.Background())
+}
+
+func (p packageInter) LanguagesContext(ctx context.Context) ([]Language, error) {
+	if err := p.endpoint(Language{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Languages(), nil
+}
+
+func (p packageInter) LanguageById(languageId string) (Language, error) {
+	return p.LanguageByIdContext(context.Background(), languageId)
+}
+
+func (p packageInter) LanguageByIdContext(ctx context.Context, languageId string) (Language, error) {
+	v, ok := p.model.LanguageById(languageId)
+	if !ok {
+		return v, notInPackage(Language{}, languageId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Problems() ([]Problem, error) {
+	return p.ProblemsContext(context.Background())
+}
+
+func (p packageInter) ProblemsContext(ctx context.Context) ([]Problem, error) {
+	if err := p.endpoint(Problem{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Problems(), nil
+}
+
+func (p packageInter) ProblemById(problemId string) (Problem, error) {
+	return p.ProblemByIdContext(context.Background(), problemId)
+}
+
+func (p packageInter) ProblemByIdContext(ctx context.Context, problemId string) (Problem, error) {
+	v, ok := p.model.ProblemById(problemId)
+	if !ok {
+		return v, notInPackage(Problem{}, problemId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Groups() ([]Group, error) {
+	return p.GroupsContext(context.Background())
+}
+
+func (p packageInter) GroupsContext(ctx context.Context) ([]Group, error) {
+	if err := p.endpoint(Group{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Groups(), nil
+}
+
+func (p packageInter) GroupById(groupId string) (Group, error) {
+	return p.GroupByIdContext(context.Background(), groupId)
+}
+
+func (p packageInter) GroupByIdContext(ctx context.Context, groupId string) (Group, error) {
+	v, ok := p.model.GroupById(groupId)
+	if !ok {
+		return v, notInPackage(Group{}, groupId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Organizations() ([]Organization, error) {
+	return p.OrganizationsContext(context.Background())
+}
+
+func (p packageInter) OrganizationsContext(ctx context.Context) ([]Organization, error) {
+	if err := p.endpoint(Organization{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Organizations(), nil
+}
+
+func (p packageInter) OrganizationById(organizationId string) (Organization, error) {
+	return p.OrganizationByIdContext(context.Background(), organizationId)
+}
+
+func (p packageInter) OrganizationByIdContext(ctx context.Context, organizationId string) (Organization, error) {
+	v, ok := p.model.OrganizationById(organizationId)
+	if !ok {
+		return v, notInPackage(Organization{}, organizationId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Teams() ([]Team, error) {
+	return p.TeamsContext(context.Background())
+}
+
+func (p packageInter) TeamsContext(ctx context.Context) ([]Team, error) {
+	if err := p.endpoint(Team{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Teams(), nil
+}
+
+func (p packageInter) TeamById(teamId string) (Team, error) {
+	return p.TeamByIdContext(context.Background(), teamId)
+}
+
+func (p packageInter) TeamByIdContext(ctx context.Context, teamId string) (Team, error) {
+	v, ok := p.model.TeamById(teamId)
+	if !ok {
+		return v, notInPackage(Team{}, teamId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Persons() ([]Person, error) {
+	return p.PersonsContext(context.Background())
+}
+
+func (p packageInter) PersonsContext(ctx context.Context) ([]Person, error) {
+	if err := p.endpoint(Person{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Persons(), nil
+}
+
+func (p packageInter) PersonById(personId string) (Person, error) {
+	return p.PersonByIdContext(context.Background(), personId)
+}
+
+func (p packageInter) PersonByIdContext(ctx context.Context, personId string) (Person, error) {
+	v, ok := p.model.PersonById(personId)
+	if !ok {
+		return v, notInPackage(Person{}, personId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Submissions() ([]Submission, error) {
+	return p.SubmissionsContext(context.Background())
+}
+
+func (p packageInter) SubmissionsContext(ctx context.Context) ([]Submission, error) {
+	if err := p.endpoint(Submission{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Submissions(), nil
+}
+
+func (p packageInter) SubmissionById(submissionId string) (Submission, error) {
+	return p.SubmissionByIdContext(context.Background(), submissionId)
+}
+
+func (p packageInter) SubmissionByIdContext(ctx context.Context, submissionId string) (Submission, error) {
+	v, ok := p.model.SubmissionById(submissionId)
+	if !ok {
+		return v, notInPackage(Submission{}, submissionId)
+	}
+
+	return v, nil
+}
+
+// SubmissionFiles reads the files of submission from submissions/<id>/
+func (p packageInter) SubmissionFiles(submission Submission) (LocalFileReference, error) {
+	return p.SubmissionFilesContext(context.Background(), submission)
+}
+
+func (p packageInter) SubmissionFilesContext(ctx context.Context, submission Submission) (files LocalFileReference, err error) {
+	if !validPathElement(submission.Id) {
+		return files, fmt.Errorf("invalid submission id %q", submission.Id)
+	}
+
+	// Exported packages contain the archive of the files, other packages only the unpacked files
+	refs := append(FileReferences{}, submission.Files...)
+	refs = append(refs, FileReference{Href: path.Join("submissions", submission.Id, "files.zip")})
+	for _, ref := range refs {
+		data, err := p.DownloadContext(ctx, ref)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return files, fmt.Errorf("could not read files of submission %v; %w", submission.Id, err)
+		}
+
+		if err := files.FromZip(data); err != nil {
+			return files, fmt.Errorf("could not unpack files of submission %v; %w", submission.Id, err)
+		}
+
+		return files, nil
+	}
+
+	root := filepath.Join(p.dir, "submissions", submission.Id)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return files, fmt.Errorf("could not find files of submission %v in contest package; %w", submission.Id, ErrNotFound)
+	}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return files.FromString(filepath.ToSlash(rel), string(contents))
+	})
+	if err != nil {
+		return files, fmt.Errorf("could not read files of submission %v; %w", submission.Id, err)
+	}
+
+	return files, nil
+}
+
+func (p packageInter) Judgements() ([]Judgement, error) {
+	return p.JudgementsContext(context.Background())
+}
+
+func (p packageInter) JudgementsContext(ctx context.Context) ([]Judgement, error) {
+	if err := p.endpoint(Judgement{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Judgements(), nil
+}
+
+func (p packageInter) JudgementById(judgementId string) (Judgement, error) {
+	return p.JudgementByIdContext(context.Background(), judgementId)
+}
+
+func (p packageInter) JudgementByIdContext(ctx context.Context, judgementId string) (Judgement, error) {
+	v, ok := p.model.JudgementById(judgementId)
+	if !ok {
+		return v, notInPackage(Judgement{}, judgementId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Runs() ([]Run, error) {
+	return p.RunsContext(context.Background())
+}
+
+func (p packageInter) RunsContext(ctx context.Context) ([]Run, error) {
+	if err := p.endpoint(Run{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Runs(), nil
+}
+
+func (p packageInter) RunById(runId string) (Run, error) {
+	return p.RunByIdContext(context.Background(), runId)
+}
+
+func (p packageInter) RunByIdContext(ctx context.Context, runId string) (Run, error) {
+	v, ok := p.model.RunById(runId)
+	if !ok {
+		return v, notInPackage(Run{}, runId)
+	}
+
+	return v, nil
+}
+
+// JudgementRuns returns the runs of the judgement with the given id, sorted by ordinal
+func (p packageInter) JudgementRuns(judgementId string) ([]Run, error) {
+	return p.JudgementRunsContext(context.Background(), judgementId)
+}
+
+func (p packageInter) JudgementRunsContext(ctx context.Context, judgementId string) ([]Run, error) {
+	if err := p.endpoint(Run{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.JudgementRuns(judgementId), nil
+}
+
+func (p packageInter) Clarifications() ([]Clarification, error) {
+	return p.ClarificationsContext(context.Background())
+}
+
+func (p packageInter) ClarificationsContext(ctx context.Context) ([]Clarification, error) {
+	if err := p.endpoint(Clarification{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Clarifications(), nil
+}
+
+func (p packageInter) ClarificationById(clarificationId string) (Clarification, error) {
+	return p.ClarificationByIdContext(context.Background(), clarificationId)
+}
+
+func (p packageInter) ClarificationByIdContext(ctx context.Context, clarificationId string) (Clarification, error) {
+	v, ok := p.model.ClarificationById(clarificationId)
+	if !ok {
+		return v, notInPackage(Clarification{}, clarificationId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Commentary() ([]Commentary, error) {
+	return p.CommentaryContext(context.Background())
+}
+
+func (p packageInter) CommentaryContext(ctx context.Context) ([]Commentary, error) {
+	if err := p.endpoint(Commentary{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Commentary(), nil
+}
+
+func (p packageInter) CommentaryById(commentaryId string) (Commentary, error) {
+	return p.CommentaryByIdContext(context.Background(), commentaryId)
+}
+
+func (p packageInter) CommentaryByIdContext(ctx context.Context, commentaryId string) (Commentary, error) {
+	v, ok := p.model.CommentaryById(commentaryId)
+	if !ok {
+		return v, notInPackage(Commentary{}, commentaryId)
+	}
+
+	return v, nil
+}
+
+func (p packageInter) Awards() ([]Award, error) {
+	return p.AwardsContext(context.Background())
+}
+
+func (p packageInter) AwardsContext(ctx context.Context) ([]Award, error) {
+	if err := p.endpoint(Award{}); err != nil {
+		return nil, err
+	}
+
+	return p.model.Awards(), nil
+}
+
+func (p packageInter) AwardById(awardId string) (Award, error) {
+	return p.AwardByIdContext(context.Background(), awardId)
+}
+
+func (p packageInter) AwardByIdContext(ctx context.Context, awardId string) (Award, error) {
+	v, ok := p.model.AwardById(awardId)
+	if !ok {
+		return v, notInPackage(Award{}, awardId)
+	}
+
+	return v, nil
+}
+
+// Scoreboard returns the scoreboard in the package, or computes it from the judgements when there is none
+func (p packageInter) Scoreboard() (Scoreboard, error) {
+	return p.ScoreboardContext(context.Background())
+}
+
+func (p packageInter) ScoreboardContext(ctx context.Context) (Scoreboard, error) {
+	if p.scoreboard != nil {
+		return *p.scoreboard, nil
+	}
+
+	return ComputeScoreboard(p.model.ScoreboardInput(), ScoreboardOptions{}), nil
+}
+
+// EventFeed opens event-feed.ndjson, stream is ignored as the feed of a package does not change
+func (p packageInter) EventFeed(stream bool) (*EventReader, error) {
+	return p.EventFeedContext(context.Background(), stream)
+}
+
+func (p packageInter) EventFeedContext(ctx context.Context, stream bool) (*EventReader, error) {
+	return p.EventFeedSinceContext(ctx, EventPosition{}, stream)
+}
+
+// EventFeedSince opens event-feed.ndjson, only returning the events after the given position
+func (p packageInter) EventFeedSince(since EventPosition, stream bool) (*EventReader, error) {
+	return p.EventFeedSinceContext(context.Background(), since, stream)
+}
+
+func (p packageInter) EventFeedSinceContext(ctx context.Context, since EventPosition, stream bool) (*EventReader, error) {
+	f, err := os.Open(filepath.Join(p.dir, "event-feed.ndjson"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("contest package does not contain an event feed; %w", ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	reader := NewEventReader(f)
+	if since.Token == "" && since.Id == "" {
+		return reader, nil
+	}
+
+	// Skip the events up to and including the one at since
+	for {
+		e, err := reader.Next()
+		if err == io.EOF {
+			reader.Close()
+			return nil, fmt.Errorf("could not find event %+v in event feed; %w", since, ErrNotFound)
+		}
+
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+
+		if (since.Token != "" && e.Token == since.Token) || (since.Token == "" && e.Id == since.Id) {
+			return reader, nil
+		}
+	}
+}
+
+// Subscribe returns a Subscription to the event feed of the package, its Next returns io.EOF after the last event of
+// the feed
+func (p packageInter) Subscribe(since EventPosition) *Subscription {
+	return p.SubscribeContext(context.Background(), since)
+}
+
+func (p packageInter) SubscribeContext(ctx context.Context, since EventPosition) *Subscription {
+	s := newSubscription(ctx, since, func(since EventPosition) (*EventReader, error) {
+		return p.EventFeedSinceContext(ctx, since, true)
+	})
+
+	// The file is not written to anymore, so reopening it would never deliver more events
+	s.ends = true
+	return s
+}
+
+func (p packageInter) Submit(submittable Submittable) (ApiType, error) {
+	return p.SubmitContext(context.Background(), submittable)
+}
+
+func (p packageInter) SubmitContext(ctx context.Context, submittable Submittable) (ApiType, error) {
+	return nil, ErrReadOnly
+}
+
+func (p packageInter) PostClarification(problemId, text string) (Clarification, error) {
+	return p.PostClarificationContext(context.Background(), problemId, text)
+}
+
+func (p packageInter) PostClarificationContext(ctx context.Context, problemId, text string) (Clarification, error) {
+	return Clarification{}, ErrReadOnly
+}
+
+func (p packageInter) PostJuryClarification(clarification Clarification) (Clarification, error) {
+	return p.PostJuryClarificationContext(context.Background(), clarification)
+}
+
+func (p packageInter) PostJuryClarificationContext(ctx context.Context, clarification Clarification) (Clarification, error) {
+	return Clarification{}, ErrReadOnly
+}
+
+func (p packageInter) PostSubmission(problemId, languageId, entrypoint string, files LocalFileReference) (Submission, error) {
+	return p.PostSubmissionContext(context.Background(), problemId, languageId, entrypoint, files)
+}
+
+func (p packageInter) PostSubmissionContext(ctx context.Context, problemId, languageId, entrypoint string, files LocalFileReference) (Submission, error) {
+	return Submission{}, ErrReadOnly
+}
+
+func (p packageInter) PostProxySubmission(submission Submission) (Submission, error) {
+	return p.PostProxySubmissionContext(context.Background(), submission)
+}
+
+func (p packageInter) PostProxySubmissionContext(ctx context.Context, submission Submission) (Submission, error) {
+	return Submission{}, ErrReadOnly
+}
+
+func (p packageInter) GetObject(interactor ApiType, id string) (ApiType, error) {
+	return p.GetObjectContext(context.Background(), interactor, id)
+}
+
+func (p packageInter) GetObjectContext(ctx context.Context, interactor ApiType, id string) (ApiType, error) {
+	switch interactor.(type) {
+	case Contest:
+		return p.ContestByIdContext(ctx, id)
+	case State:
+		// The state and scoreboard are singletons, like for the API the id is ignored
+		return p.StateContext(ctx)
+	case Scoreboard:
+		return p.ScoreboardContext(ctx)
+	case JudgementType:
+		return p.JudgementTypeByIdContext(ctx, id)
+	case Language:
+		return p.LanguageByIdContext(ctx, id)
+	case Problem:
+		return p.ProblemByIdContext(ctx, id)
+	case Group:
+		return p.GroupByIdContext(ctx, id)
+	case Organization:
+		return p.OrganizationByIdContext(ctx, id)
+	case Team:
+		return p.TeamByIdContext(ctx, id)
+	case Person:
+		return p.PersonByIdContext(ctx, id)
+	case Submission:
+		return p.SubmissionByIdContext(ctx, id)
+	case Judgement:
+		return p.JudgementByIdContext(ctx, id)
+	case Run:
+		return p.RunByIdContext(ctx, id)
+	case Clarification:
+		return p.ClarificationByIdContext(ctx, id)
+	case Award:
+		return p.AwardByIdContext(ctx, id)
+	case Commentary:
+		return p.CommentaryByIdContext(ctx, id)
+	}
+
+	return nil, notInPackage(interactor, id)
+}
+
+func (p packageInter) GetObjects(interactor ApiType) ([]ApiType, error) {
+	return p.GetObjectsContext(context.Background(), interactor)
+}
+
+func (p packageInter) GetObjectsContext(ctx context.Context, interactor ApiType) ([]ApiType, error) {
+	switch interactor.(type) {
+	case Contest:
+		return []ApiType{p.model.Contest()}, nil
+	case State:
+		return []ApiType{p.model.State()}, nil
+	case Scoreboard:
+		scoreboard, err := p.ScoreboardContext(ctx)
+		return []ApiType{scoreboard}, err
+	}
+
+	if err := p.endpoint(interactor); err != nil {
+		return nil, err
+	}
+
+	ret := make([]ApiType, 0)
+	switch interactor.(type) {
+	case JudgementType:
+		for _, v := range p.model.JudgementTypes() {
+			ret = append(ret, v)
+		}
+	case Language:
+		for _, v := range p.model.Languages() {
+			ret = append(ret, v)
+		}
+	case Problem:
+		for _, v := range p.model.Problems() {
+			ret = append(ret, v)
+		}
+	case Group:
+		for _, v := range p.model.Groups() {
+			ret = append(ret, v)
+		}
+	case Organization:
+		for _, v := range p.model.Organizations() {
+			ret = append(ret, v)
+		}
+	case Team:
+		for _, v := range p.model.Teams() {
+			ret = append(ret, v)
+		}
+	case Person:
+		for _, v := range p.model.Persons() {
+			ret = append(ret, v)
+		}
+	case Submission:
+		for _, v := range p.model.Submissions() {
+			ret = append(ret, v)
+		}
+	case Judgement:
+		for _, v := range p.model.Judgements() {
+			ret = append(ret, v)
+		}
+	case Run:
+		for _, v := range p.model.Runs() {
+			ret = append(ret, v)
+		}
+	case Clarification:
+		for _, v := range p.model.Clarifications() {
+			ret = append(ret, v)
+		}
+	case Award:
+		for _, v := range p.model.Awards() {
+			ret = append(ret, v)
+		}
+	case Commentary:
+		for _, v := range p.model.Commentary() {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,184 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// exportedPackage exports the contest of a MockServer with default data to a temporary directory, which should be
+// removed after use
+func exportedPackage(t *testing.T) string {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	api, err := ContestInteractor(server.URL, "admin", "admin", "nwerc18", false)
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "package")
+	assert.Nil(t, err)
+	assert.Nil(t, ExportContest(context.Background(), api, dir, ExportOptions{}))
+	return dir
+}
+
+func TestPackageInteractor(t *testing.T) {
+	dir := exportedPackage(t)
+	defer os.RemoveAll(dir)
+
+	api, err := PackageInteractor(dir)
+	assert.Nil(t, err)
+
+	t.Run("objects", func(t *testing.T) {
+		contest, err := api.Contest()
+		assert.Nil(t, err)
+		assert.EqualValues(t, "nwerc18", contest.Id)
+
+		submissions, err := api.Submissions()
+		assert.Nil(t, err)
+		assert.Len(t, submissions, 3)
+
+		judgement, err := api.JudgementById("j2")
+		assert.Nil(t, err)
+		assert.EqualValues(t, "AC", judgement.JudgementTypeId)
+
+		runs, err := api.JudgementRuns("j1")
+		assert.Nil(t, err)
+		assert.Len(t, runs, 2)
+
+		_, err = api.TeamById("missing")
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		_, err = api.Accounts()
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		teams, err := api.GetObjects(Team{})
+		assert.Nil(t, err)
+		assert.Len(t, teams, 2)
+
+		state, err := api.GetObject(State{}, "")
+		assert.Nil(t, err)
+		assert.IsType(t, State{}, state)
+
+		scoreboard, err := api.GetObject(Scoreboard{}, "")
+		assert.Nil(t, err)
+		assert.IsType(t, Scoreboard{}, scoreboard)
+	})
+
+	t.Run("scoreboard", func(t *testing.T) {
+		expected := DefaultMockData().Contests[0].Scoreboard
+
+		scoreboard, err := api.Scoreboard()
+		assert.Nil(t, err)
+		assert.EqualValues(t, expected.Rows, scoreboard.Rows)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		submission, err := api.SubmissionById("s3")
+		assert.Nil(t, err)
+
+		files, err := api.SubmissionFiles(submission)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"Main.java"}, files.Names())
+
+		organization, err := api.OrganizationById("uva")
+		assert.Nil(t, err)
+
+		logo, ok := BestFile(organization.Logo, 256, 256)
+		assert.True(t, ok)
+		data, err := api.Download(logo)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "\x89PNG uva 256x256", string(data))
+
+		_, err = api.Download(FileReference{Href: "../outside.png"})
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("event-feed", func(t *testing.T) {
+		feed, err := api.EventFeed(false)
+		assert.Nil(t, err)
+		events := readAllEvents(t, feed)
+		if assert.NotEmpty(t, events) {
+			assert.EqualValues(t, "contest", events[0].Type)
+		}
+
+		feed, err = api.EventFeedSince(EventPosition{Token: events[0].Token}, false)
+		assert.Nil(t, err)
+		assert.Len(t, readAllEvents(t, feed), len(events)-1)
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		feed, err := api.EventFeed(false)
+		assert.Nil(t, err)
+		events := readAllEvents(t, feed)
+
+		sub := api.Subscribe(EventPosition{})
+		defer sub.Close()
+
+		for range events {
+			_, err := sub.Next()
+			assert.Nil(t, err)
+		}
+
+		// The end of the feed is reported immediately, also when calling Next again
+		start := time.Now()
+		for k := 0; k < 2; k++ {
+			_, err = sub.Next()
+			assert.True(t, errors.Is(err, io.EOF))
+		}
+		assert.True(t, time.Since(start) < time.Second)
+
+		// A position that is not in the feed is not retried
+		sub = api.Subscribe(EventPosition{Token: "missing"})
+		defer sub.Close()
+
+		_, err = sub.Next()
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.True(t, time.Since(start) < time.Second)
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		var files LocalFileReference
+		_ = files.FromString("main.py", "print(42)")
+		_, err := api.PostSubmission("brexit", "python3", "", files)
+		assert.True(t, errors.Is(err, ErrReadOnly))
+
+		_, err = api.PostClarification("brexit", "Is this read-only?")
+		assert.True(t, errors.Is(err, ErrReadOnly))
+	})
+}
+
+func TestPackageInteractorEventFeed(t *testing.T) {
+	dir := exportedPackage(t)
+	defer os.RemoveAll(dir)
+
+	// Without contest.json the contest is read from the event feed
+	assert.Nil(t, os.Remove(filepath.Join(dir, "contest.json")))
+	assert.Nil(t, os.Remove(filepath.Join(dir, "scoreboard.json")))
+
+	api, err := PackageInteractor(dir)
+	assert.Nil(t, err)
+
+	contest, err := api.Contest()
+	assert.Nil(t, err)
+	assert.EqualValues(t, "nwerc18", contest.Id)
+
+	problems, err := api.Problems()
+	assert.Nil(t, err)
+	assert.Len(t, problems, 3)
+
+	scoreboard, err := api.Scoreboard()
+	assert.Nil(t, err)
+	if assert.Len(t, scoreboard.Rows, 2) {
+		assert.EqualValues(t, "2", scoreboard.Rows[0].TeamId)
+	}
+
+	_, err = PackageInteractor(filepath.Join(dir, "missing"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,172 @@
+package interactor
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type (
+	// RetryPolicy determines how failed idempotent requests are retried. Requests are retried when the connection
+	// fails or when the server responds with a statuscode indicating a temporary failure (408, 429 and 5xx).
+	RetryPolicy struct {
+		// MaxAttempts is the maximum number of attempts including the first one, retrying is disabled when it is at most 1
+		MaxAttempts int
+		// InitialBackoff is the time waited before the first retry
+		InitialBackoff time.Duration
+		// MaxBackoff caps the time waited between two attempts, unless the server asks for more using Retry-After
+		MaxBackoff time.Duration
+		// Multiplier is the factor by which the backoff grows after every attempt
+		Multiplier float64
+		// Jitter is the fraction, between 0 and 1, by which the backoff is randomly increased or decreased
+		Jitter float64
+		// MaxElapsed caps the total time spent on a request including all retries, 0 for no limit
+		MaxElapsed time.Duration
+		// OnRetry is called before waiting for the next attempt, it can be used for logging
+		OnRetry func(info RetryInfo)
+	}
+
+	// RetryInfo describes a retry that is about to happen
+	RetryInfo struct {
+		Method string
+		URL    string
+		// Attempt is the number of the attempt that failed, starting at 1
+		Attempt int
+		// Err is the error of the failed attempt, nil when the server responded
+		Err error
+		// StatusCode is the statuscode of the failed attempt, 0 when the server did not respond
+		StatusCode int
+		// Wait is the time that is waited before the next attempt
+		Wait time.Duration
+	}
+)
+
+// DefaultRetryPolicy returns a RetryPolicy suitable for most contests, making at most 5 attempts within 30 seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsed:     30 * time.Second,
+	}
+}
+
+// backoff returns the time to wait after the given attempt failed
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff)
+	for k := 1; k < attempt; k++ {
+		backoff *= p.Multiplier
+	}
+
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		backoff *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(backoff)
+}
+
+// retryable reports whether an attempt resulting in resp and err can be retried
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// Cancellation and untrusted certificates will not be solved by retrying
+		return ctx.Err() == nil && !IsTrustError(err)
+	}
+
+	return (&APIError{StatusCode: resp.StatusCode}).Temporary()
+}
+
+// retryAfter parses the Retry-After header of resp, which is either a number of seconds or a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// do performs req, retrying it according to the RetryPolicy of the interactor when idempotent is true. The body of
+// req can only be sent again when req.GetBody is set.
+func (i inter) do(req *http.Request, idempotent bool) (*http.Response, error) {
+	p := i.retry
+	if !idempotent || p.MaxAttempts <= 1 || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return i.Do(req)
+	}
+
+	ctx := req.Context()
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			// Rewind the body for this attempt
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := i.Do(r)
+		if attempt >= p.MaxAttempts || !retryable(ctx, resp, err) {
+			return resp, err
+		}
+
+		wait, ok := retryAfter(resp)
+		if !ok {
+			wait = p.backoff(attempt)
+		}
+
+		if p.MaxElapsed > 0 && time.Since(start)+wait > p.MaxElapsed {
+			// There is no time for another attempt, return the result of this one
+			return resp, err
+		}
+
+		info := RetryInfo{Method: req.Method, URL: req.URL.String(), Attempt: attempt, Err: err, Wait: wait}
+		if resp != nil {
+			info.StatusCode = resp.StatusCode
+
+			// Drain the body, such that the connection can be reused
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if p.OnRetry != nil {
+			p.OnRetry(info)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
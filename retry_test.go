@@ -0,0 +1,120 @@
+package interactor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var failures, attempts, posts int32
+
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/problems":
+			atomic.AddInt32(&attempts, 1)
+			if atomic.AddInt32(&failures, -1) >= 0 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			_, _ = w.Write([]byte(`[{"id":"A"}]`))
+		case "/contests/test/clarifications":
+			atomic.AddInt32(&posts, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ser.Close()
+
+	var retries []RetryInfo
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.OnRetry = func(info RetryInfo) {
+		retries = append(retries, info)
+	}
+
+	api, err := NewContestInteractor(ser.URL, "test", WithRetryPolicy(policy))
+	assert.Nil(t, err)
+
+	t.Run("recovers", func(t *testing.T) {
+		retries = nil
+		atomic.StoreInt32(&attempts, 0)
+		atomic.StoreInt32(&failures, 2)
+
+		problems, err := api.Problems()
+		assert.Nil(t, err)
+		assert.Len(t, problems, 1)
+		assert.EqualValues(t, 3, atomic.LoadInt32(&attempts))
+		assert.Len(t, retries, 2)
+		assert.EqualValues(t, http.StatusServiceUnavailable, retries[0].StatusCode)
+		assert.EqualValues(t, 0, retries[0].Wait)
+		assert.EqualValues(t, 2, retries[1].Attempt)
+	})
+
+	t.Run("gives-up", func(t *testing.T) {
+		retries = nil
+		atomic.StoreInt32(&attempts, 0)
+		atomic.StoreInt32(&failures, 100)
+
+		_, err := api.Problems()
+		assert.NotNil(t, err)
+		assert.EqualValues(t, policy.MaxAttempts, atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("post-without-id", func(t *testing.T) {
+		retries = nil
+		atomic.StoreInt32(&posts, 0)
+
+		_, err := api.PostClarification("A", "not retried")
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&posts))
+	})
+
+	t.Run("post-with-id", func(t *testing.T) {
+		retries = nil
+		atomic.StoreInt32(&posts, 0)
+
+		_, err := api.Submit(Clarification{Id: "c1", Text: "retried"})
+		assert.NotNil(t, err)
+		assert.EqualValues(t, policy.MaxAttempts, atomic.LoadInt32(&posts))
+	})
+
+	t.Run("max-elapsed", func(t *testing.T) {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer slow.Close()
+
+		policy := policy
+		policy.InitialBackoff = time.Second
+		policy.MaxElapsed = 100 * time.Millisecond
+		api, err := NewContestsInteractor(slow.URL, WithRetryPolicy(policy))
+		assert.Nil(t, err)
+
+		// Waiting for the backoff would exceed the maximum, so the request should fail immediately
+		start := time.Now()
+		_, err = api.Contests()
+		assert.NotNil(t, err)
+		assert.True(t, time.Since(start) < policy.InitialBackoff)
+	})
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+	assert.EqualValues(t, time.Second, p.backoff(1))
+	assert.EqualValues(t, 4*time.Second, p.backoff(3))
+	assert.EqualValues(t, 5*time.Second, p.backoff(10))
+
+	p.Jitter = 0.5
+	for k := 0; k < 100; k++ {
+		b := p.backoff(1)
+		assert.True(t, b >= 500*time.Millisecond && b <= 1500*time.Millisecond)
+	}
+}
@@ -79,6 +79,48 @@ func (s Scoreboard) Generate() ApiType {
 	return Scoreboard{}
 }
 
+func (s *Score) UnmarshalJSON(data []byte) error {
+	type plain Score
+	var v struct {
+		plain
+		TotalTime json.RawMessage `json:"total_time"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	totalTime, err := decodeMinutes(v.TotalTime)
+	if err != nil {
+		return fmt.Errorf("invalid total time; %w", err)
+	}
+
+	*s = Score(v.plain)
+	s.TotalTime = totalTime
+	return nil
+}
+
+func (s *ScoreProblem) UnmarshalJSON(data []byte) error {
+	type plain ScoreProblem
+	var v struct {
+		plain
+		Time json.RawMessage `json:"time"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	t, err := decodeMinutes(v.Time)
+	if err != nil {
+		return fmt.Errorf("invalid time; %w", err)
+	}
+
+	*s = ScoreProblem(v.plain)
+	s.Time = t
+	return nil
+}
+
 func (r Row) String() string {
 	problems := make([]string, len(r.Problems))
 	for k, problem := range r.Problems {
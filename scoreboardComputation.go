@@ -0,0 +1,194 @@
+package interactor
+
+import (
+	"sort"
+	"time"
+)
+
+type (
+	// ScoreboardInput contains all data needed to compute a scoreboard
+	ScoreboardInput struct {
+		Contest        Contest
+		State          State
+		Problems       []Problem
+		Groups         []Group
+		Teams          []Team
+		Submissions    []Submission
+		Judgements     []Judgement
+		JudgementTypes []JudgementType
+	}
+
+	// ScoreboardOptions determine which scoreboard is computed by ComputeScoreboard
+	ScoreboardOptions struct {
+		// ContestTime is the contest time at which the scoreboard is computed. Submissions made at or after this time
+		// are ignored and judgements that ended after it are considered pending. When 0, all data is used.
+		ContestTime ApiRelTime
+		// Public hides the results of submissions made during the scoreboard freeze, they are counted as pending
+		// instead. It has no effect when the contest has no freeze or the scoreboard has been thawed.
+		Public bool
+	}
+
+	// problemResult keeps track of a single team on a single problem
+	problemResult struct {
+		ScoreProblem
+		penalties int
+	}
+)
+
+// DefaultPenaltyTime is the penalty time in minutes used when the contest does not specify one
+const DefaultPenaltyTime = 20
+
+// ComputeScoreboard computes the scoreboard using the ICPC pass-fail rules. Teams are ranked on the number of
+// solved problems, the total time and the minute of their last solve, teams which are equal on all three share a rank.
+// The total time is the sum of the minutes at which problems were solved, plus the penalty time for every rejected
+// submission before a solve, only judgements of a type with Penalty set are penalized. Teams that are only in hidden
+// groups are left out and submissions made before the contest started or after it ended are ignored. The state of
+// the scoreboard is copied from the input.
+func ComputeScoreboard(input ScoreboardInput, opts ScoreboardOptions) Scoreboard {
+	contestTime := opts.ContestTime
+	if contestTime <= 0 {
+		contestTime = input.Contest.Duration
+	}
+
+	// The freeze only applies when the public scoreboard has not been thawed yet
+	freezeTime := ApiRelTime(-1)
+	if opts.Public && input.Contest.ScoreboardFreezeDuration > 0 && input.State.Thawed == nil {
+		freezeTime = input.Contest.Duration - input.Contest.ScoreboardFreezeDuration
+	}
+
+	penaltyTime := DefaultPenaltyTime
+	if input.Contest.PenaltyTime != nil {
+		penaltyTime = *input.Contest.PenaltyTime
+	}
+
+	problems := make([]Problem, len(input.Problems))
+	copy(problems, input.Problems)
+	sort.SliceStable(problems, func(a, b int) bool { return problems[a].Ordinal < problems[b].Ordinal })
+
+	judgementTypes := make(map[string]JudgementType, len(input.JudgementTypes))
+	for _, jt := range input.JudgementTypes {
+		judgementTypes[jt.Id] = jt
+	}
+
+	// The current judgement of a submission is the last one started before the scoreboard time
+	judgements := make(map[string]Judgement)
+	for _, j := range input.Judgements {
+		if opts.ContestTime > 0 && j.StartContestTime >= opts.ContestTime {
+			continue
+		}
+
+		if current, ok := judgements[j.SubmissionId]; !ok || current.StartContestTime <= j.StartContestTime {
+			judgements[j.SubmissionId] = j
+		}
+	}
+
+	results := make(map[string]map[string]*problemResult)
+	for _, team := range scoreboardTeams(input) {
+		results[team.Id] = make(map[string]*problemResult, len(problems))
+		for _, p := range problems {
+			results[team.Id][p.Id] = &problemResult{ScoreProblem: ScoreProblem{ProblemId: Identifier(p.Id)}}
+		}
+	}
+
+	submissions := make([]Submission, len(input.Submissions))
+	copy(submissions, input.Submissions)
+	sort.SliceStable(submissions, func(a, b int) bool { return submissions[a].ContestTime < submissions[b].ContestTime })
+
+	for _, s := range submissions {
+		result, ok := results[s.TeamId][s.ProblemId]
+		if !ok || result.Solved || s.ContestTime < 0 || (contestTime > 0 && s.ContestTime >= contestTime) {
+			continue
+		}
+
+		j, judged := judgements[s.Id]
+		judged = judged && j.JudgementTypeId != "" && j.EndTime != nil &&
+			(opts.ContestTime <= 0 || j.EndContestTime <= opts.ContestTime)
+		jt, known := judgementTypes[j.JudgementTypeId]
+		if !judged || !known || (freezeTime >= 0 && s.ContestTime >= freezeTime) {
+			result.NumPending++
+			continue
+		}
+
+		result.NumJudged++
+		if jt.Solved {
+			result.Solved = true
+			result.Time = int(s.ContestTime.Duration() / time.Minute)
+		} else if jt.Penalty {
+			result.penalties++
+		}
+	}
+
+	rows := make([]Row, 0, len(results))
+	// The last solve is compared in minutes, like the total time
+	lastSolves := make(map[string]int, len(results))
+	for teamId, teamResults := range results {
+		row := Row{TeamId: Identifier(teamId), Problems: make([]ScoreProblem, len(problems))}
+		for k, p := range problems {
+			result := teamResults[p.Id]
+			row.Problems[k] = result.ScoreProblem
+			if !result.Solved {
+				continue
+			}
+
+			row.Score.NumSolved++
+			row.Score.TotalTime += result.Time + result.penalties*penaltyTime
+			if result.Time > lastSolves[teamId] {
+				lastSolves[teamId] = result.Time
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	better := func(a, b Row) bool {
+		if a.Score.NumSolved != b.Score.NumSolved {
+			return a.Score.NumSolved > b.Score.NumSolved
+		}
+		if a.Score.TotalTime != b.Score.TotalTime {
+			return a.Score.TotalTime < b.Score.TotalTime
+		}
+
+		return lastSolves[string(a.TeamId)] < lastSolves[string(b.TeamId)]
+	}
+
+	// Teams with the same rank are ordered by id, such that the result is deterministic
+	sort.Slice(rows, func(a, b int) bool {
+		return better(rows[a], rows[b]) || (!better(rows[b], rows[a]) && rows[a].TeamId < rows[b].TeamId)
+	})
+
+	for k := range rows {
+		rows[k].Rank = k + 1
+		if k > 0 && !better(rows[k-1], rows[k]) {
+			rows[k].Rank = rows[k-1].Rank
+		}
+	}
+
+	return Scoreboard{
+		Time:        input.Contest.StartTime.AddDuration(contestTime),
+		ContestTime: contestTime,
+		State:       input.State,
+		Rows:        rows,
+	}
+}
+
+// scoreboardTeams returns the teams that should be shown on the scoreboard
+func scoreboardTeams(input ScoreboardInput) []Team {
+	hidden := make(map[string]bool, len(input.Groups))
+	for _, g := range input.Groups {
+		hidden[g.Id] = g.Hidden
+	}
+
+	var teams []Team
+	for _, team := range input.Teams {
+		visible := len(team.GroupIds) == 0
+		for _, groupId := range team.GroupIds {
+			visible = visible || !hidden[groupId]
+		}
+
+		if visible {
+			teams = append(teams, team)
+		}
+	}
+
+	return teams
+}
@@ -0,0 +1,175 @@
+package interactor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeScoreboard(t *testing.T) {
+	minutes := func(m int) ApiRelTime {
+		return ApiRelTime(time.Duration(m) * time.Minute)
+	}
+	judged := func(id, submissionId, judgementTypeId string, m int) Judgement {
+		end := ApiTime(time.Unix(0, 0))
+		return Judgement{Id: id, SubmissionId: submissionId, JudgementTypeId: judgementTypeId,
+			StartContestTime: minutes(m), EndTime: &end, EndContestTime: minutes(m + 1)}
+	}
+
+	input := ScoreboardInput{
+		Contest: Contest{Id: "test", Duration: minutes(300), ScoreboardFreezeDuration: minutes(60)},
+		Problems: []Problem{
+			{Id: "b", Label: "B", Ordinal: 1},
+			{Id: "a", Label: "A", Ordinal: 0},
+		},
+		Groups: []Group{{Id: "visible"}, {Id: "hidden", Hidden: true}},
+		Teams: []Team{
+			{Id: "t1", GroupIds: []string{"visible"}},
+			{Id: "t2", GroupIds: []string{"visible"}},
+			{Id: "t3"},
+			{Id: "t4", GroupIds: []string{"hidden"}},
+		},
+		JudgementTypes: []JudgementType{
+			{Id: "AC", Solved: true},
+			{Id: "WA", Penalty: true},
+			{Id: "CE"},
+		},
+		Submissions: []Submission{
+			{Id: "s1", TeamId: "t1", ProblemId: "a", ContestTime: minutes(10)},
+			{Id: "s2", TeamId: "t1", ProblemId: "a", ContestTime: minutes(20)},
+			{Id: "s3", TeamId: "t1", ProblemId: "a", ContestTime: minutes(25)},
+			{Id: "s4", TeamId: "t2", ProblemId: "a", ContestTime: minutes(15)},
+			{Id: "s5", TeamId: "t2", ProblemId: "a", ContestTime: minutes(35)},
+			{Id: "s6", TeamId: "t2", ProblemId: "b", ContestTime: minutes(250)},
+			{Id: "s7", TeamId: "t3", ProblemId: "a", ContestTime: minutes(5)},
+			{Id: "s8", TeamId: "t3", ProblemId: "a", ContestTime: minutes(40)},
+			{Id: "s9", TeamId: "t4", ProblemId: "a", ContestTime: minutes(1)},
+			{Id: "s10", TeamId: "t3", ProblemId: "b", ContestTime: minutes(310)},
+		},
+		Judgements: []Judgement{
+			judged("j1", "s1", "WA", 10),
+			judged("j2", "s2", "AC", 20),
+			judged("j3", "s3", "WA", 25),
+			judged("j4", "s4", "CE", 15),
+			judged("j5", "s5", "AC", 35),
+			judged("j6", "s6", "AC", 250),
+			judged("j7", "s7", "WA", 5),
+			judged("j8", "s8", "AC", 40),
+			// A rejudge of s7 which turns it into a compiler error
+			judged("j9", "s7", "CE", 100),
+			judged("j10", "s9", "AC", 1),
+			judged("j11", "s10", "AC", 310),
+		},
+	}
+
+	rowsByTeam := func(s Scoreboard) map[Identifier]Row {
+		rows := make(map[Identifier]Row)
+		for _, row := range s.Rows {
+			rows[row.TeamId] = row
+		}
+
+		return rows
+	}
+
+	t.Run("final", func(t *testing.T) {
+		s := ComputeScoreboard(input, ScoreboardOptions{})
+		assert.EqualValues(t, minutes(300), s.ContestTime)
+		if !assert.Len(t, s.Rows, 3) {
+			return
+		}
+
+		// Team 2 solved two problems, team 1 and 3 both have 40 minutes but team 1 solved earlier
+		assert.EqualValues(t, "t2", s.Rows[0].TeamId)
+		assert.EqualValues(t, Score{NumSolved: 2, TotalTime: 285}, s.Rows[0].Score)
+		assert.EqualValues(t, "t1", s.Rows[1].TeamId)
+		assert.EqualValues(t, Score{NumSolved: 1, TotalTime: 40}, s.Rows[1].Score)
+		assert.EqualValues(t, "t3", s.Rows[2].TeamId)
+		assert.EqualValues(t, Score{NumSolved: 1, TotalTime: 40}, s.Rows[2].Score)
+		assert.EqualValues(t, []int{1, 2, 3}, []int{s.Rows[0].Rank, s.Rows[1].Rank, s.Rows[2].Rank})
+
+		// Problems are ordered by ordinal, submissions after the first solve are not counted
+		assert.EqualValues(t, []ScoreProblem{
+			{ProblemId: "a", NumJudged: 2, Solved: true, Time: 20},
+			{ProblemId: "b"},
+		}, s.Rows[1].Problems)
+	})
+
+	t.Run("contest-time", func(t *testing.T) {
+		// At 30 minutes the rejudge has not happened and the correct submission of team 3 has not been made
+		rows := rowsByTeam(ComputeScoreboard(input, ScoreboardOptions{ContestTime: minutes(30)}))
+		assert.EqualValues(t, Score{NumSolved: 1, TotalTime: 40}, rows["t1"].Score)
+		assert.EqualValues(t, 1, rows["t1"].Rank)
+		assert.EqualValues(t, 0, rows["t2"].Score.NumSolved)
+		assert.EqualValues(t, 2, rows["t3"].Rank)
+		assert.EqualValues(t, 2, rows["t2"].Rank)
+		assert.EqualValues(t, 1, rows["t3"].Problems[0].NumJudged)
+
+		// The judgement of s2 ends after 21 minutes
+		rows = rowsByTeam(ComputeScoreboard(input, ScoreboardOptions{ContestTime: minutes(21) - 1}))
+		assert.EqualValues(t, 1, rows["t1"].Problems[0].NumPending)
+		assert.False(t, rows["t1"].Problems[0].Solved)
+	})
+
+	t.Run("public", func(t *testing.T) {
+		rows := rowsByTeam(ComputeScoreboard(input, ScoreboardOptions{Public: true}))
+		assert.EqualValues(t, 1, rows["t2"].Score.NumSolved)
+		assert.EqualValues(t, ScoreProblem{ProblemId: "b", NumPending: 1}, rows["t2"].Problems[1])
+
+		// After thawing the public scoreboard equals the final one
+		thawed := input
+		thawed.State.Thawed = new(ApiTime)
+		assert.EqualValues(t, ComputeScoreboard(input, ScoreboardOptions{}).Rows,
+			ComputeScoreboard(thawed, ScoreboardOptions{Public: true}).Rows)
+	})
+
+	t.Run("penalty-time", func(t *testing.T) {
+		penaltyTime := 5
+		custom := input
+		custom.Contest.PenaltyTime = &penaltyTime
+		rows := rowsByTeam(ComputeScoreboard(custom, ScoreboardOptions{}))
+		assert.EqualValues(t, 25, rows["t1"].Score.TotalTime)
+
+		// A contest without penalty is not given the default penalty time
+		noPenalty := 0
+		custom.Contest.PenaltyTime = &noPenalty
+		rows = rowsByTeam(ComputeScoreboard(custom, ScoreboardOptions{}))
+		assert.EqualValues(t, 20, rows["t1"].Score.TotalTime)
+
+		contest, err := Contest{}.FromJSON([]byte(`{"id":"c","penalty_time":0}`))
+		assert.Nil(t, err)
+		custom.Contest.PenaltyTime = contest.(Contest).PenaltyTime
+		assert.EqualValues(t, rows, rowsByTeam(ComputeScoreboard(custom, ScoreboardOptions{})))
+	})
+
+	t.Run("same-minute", func(t *testing.T) {
+		// Last solves are compared in minutes, so solving 40 seconds later in the same minute gives the same rank
+		custom := input
+		custom.Submissions = []Submission{
+			{Id: "s1", TeamId: "t1", ProblemId: "a", ContestTime: minutes(10) + ApiRelTime(10*time.Second)},
+			{Id: "s2", TeamId: "t2", ProblemId: "a", ContestTime: minutes(10) + ApiRelTime(50*time.Second)},
+		}
+		custom.Judgements = []Judgement{judged("j1", "s1", "AC", 11), judged("j2", "s2", "AC", 11)}
+
+		rows := rowsByTeam(ComputeScoreboard(custom, ScoreboardOptions{}))
+		assert.EqualValues(t, 1, rows["t1"].Rank)
+		assert.EqualValues(t, 1, rows["t2"].Rank)
+	})
+
+	t.Run("mock-server", func(t *testing.T) {
+		server := NewMockServer(DefaultMockData())
+		defer server.Close()
+
+		api, err := ContestInteractor(server.URL, "admin", "admin", "nwerc18", false)
+		assert.Nil(t, err)
+
+		m, err := LoadContestModel(api)
+		assert.Nil(t, err)
+
+		expected, err := api.Scoreboard()
+		assert.Nil(t, err)
+
+		s := ComputeScoreboard(m.ScoreboardInput(), ScoreboardOptions{})
+		assert.EqualValues(t, expected.Rows, s.Rows)
+	})
+}
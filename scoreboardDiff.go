@@ -0,0 +1,206 @@
+package interactor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// ScoreboardDiff describes the differences between two scoreboards
+	ScoreboardDiff struct {
+		OldContestTime ApiRelTime
+		NewContestTime ApiRelTime
+		// Added contains the rows of teams that are only on the new scoreboard
+		Added []Row
+		// Removed contains the rows of teams that are only on the old scoreboard
+		Removed []Row
+		// Changed contains the teams on both scoreboards whose rank, score or problems changed, ordered by new rank
+		Changed []TeamChange
+	}
+
+	// TeamChange describes the changes of a single team between two scoreboards
+	TeamChange struct {
+		TeamId   Identifier
+		OldRank  int
+		NewRank  int
+		OldScore Score
+		NewScore Score
+		// Problems contains the problems of the team that changed, in the order of the new scoreboard followed by the
+		// problems that are only on the old scoreboard
+		Problems []ProblemChange
+	}
+
+	// ProblemChange describes the changes of a single problem of a team between two scoreboards
+	ProblemChange struct {
+		ProblemId Identifier
+		Old       ScoreProblem
+		New       ScoreProblem
+	}
+)
+
+// DiffScoreboards compares the scoreboard before with the scoreboard after, matching rows on team id and problems on
+// problem id. A problem that is only in one of the rows of a team is compared with an empty problem.
+func DiffScoreboards(before, after Scoreboard) ScoreboardDiff {
+	diff := ScoreboardDiff{
+		OldContestTime: before.ContestTime,
+		NewContestTime: after.ContestTime,
+	}
+
+	oldRows := make(map[Identifier]Row, len(before.Rows))
+	for _, row := range before.Rows {
+		oldRows[row.TeamId] = row
+	}
+
+	newRows := make(map[Identifier]bool, len(after.Rows))
+	for _, row := range after.Rows {
+		newRows[row.TeamId] = true
+
+		oldRow, ok := oldRows[row.TeamId]
+		if !ok {
+			diff.Added = append(diff.Added, row)
+			continue
+		}
+
+		if change := diffRows(oldRow, row); change.Changed() {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+
+	for _, row := range before.Rows {
+		if !newRows[row.TeamId] {
+			diff.Removed = append(diff.Removed, row)
+		}
+	}
+
+	sort.SliceStable(diff.Changed, func(a, b int) bool { return diff.Changed[a].NewRank < diff.Changed[b].NewRank })
+	return diff
+}
+
+func diffRows(before, after Row) TeamChange {
+	change := TeamChange{
+		TeamId:   after.TeamId,
+		OldRank:  before.Rank,
+		NewRank:  after.Rank,
+		OldScore: before.Score,
+		NewScore: after.Score,
+	}
+
+	oldProblems := make(map[Identifier]ScoreProblem, len(before.Problems))
+	for _, p := range before.Problems {
+		oldProblems[p.ProblemId] = p
+	}
+
+	newProblems := make(map[Identifier]bool, len(after.Problems))
+	for _, p := range after.Problems {
+		newProblems[p.ProblemId] = true
+
+		// A problem missing from the old row is treated as an empty one
+		oldProblem, ok := oldProblems[p.ProblemId]
+		if !ok {
+			oldProblem = ScoreProblem{ProblemId: p.ProblemId}
+		}
+
+		if oldProblem != p {
+			change.Problems = append(change.Problems, ProblemChange{ProblemId: p.ProblemId, Old: oldProblem, New: p})
+		}
+	}
+
+	// Likewise a problem missing from the new row is treated as an empty one
+	for _, p := range before.Problems {
+		if newProblem := (ScoreProblem{ProblemId: p.ProblemId}); !newProblems[p.ProblemId] && p != newProblem {
+			change.Problems = append(change.Problems, ProblemChange{ProblemId: p.ProblemId, Old: p, New: newProblem})
+		}
+	}
+
+	return change
+}
+
+// Empty returns whether the scoreboards are equal, apart from their time
+func (d ScoreboardDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d ScoreboardDiff) String() string {
+	added := make([]string, len(d.Added))
+	for k, row := range d.Added {
+		added[k] = row.String()
+	}
+
+	removed := make([]string, len(d.Removed))
+	for k, row := range d.Removed {
+		removed[k] = row.String()
+	}
+
+	changed := make([]string, len(d.Changed))
+	for k, change := range d.Changed {
+		changed[k] = change.String()
+	}
+
+	return fmt.Sprintf(`
+old contest time: %v
+new contest time: %v
+           added: %v
+         removed: %v
+         changed: %v
+`, d.OldContestTime, d.NewContestTime, strings.Join(added, ""), strings.Join(removed, ""), strings.Join(changed, ""))
+}
+
+// Changed returns whether the rank, score or any problem of the team changed
+func (c TeamChange) Changed() bool {
+	return c.OldRank != c.NewRank || c.OldScore != c.NewScore || len(c.Problems) > 0
+}
+
+// RankChange returns the number of places the team moved up, negative when the team dropped
+func (c TeamChange) RankChange() int {
+	return c.OldRank - c.NewRank
+}
+
+// NewlySolved returns the problems the team solved since the old scoreboard
+func (c TeamChange) NewlySolved() []Identifier {
+	var solved []Identifier
+	for _, p := range c.Problems {
+		if p.NewlySolved() {
+			solved = append(solved, p.ProblemId)
+		}
+	}
+
+	return solved
+}
+
+func (c TeamChange) String() string {
+	problems := make([]string, len(c.Problems))
+	for k, problem := range c.Problems {
+		problems[k] = problem.String()
+	}
+
+	return fmt.Sprintf(`
+     team id: %v
+        rank: %v -> %v
+  num solved: %v -> %v
+  total time: %v -> %v
+    problems: %v
+`, c.TeamId, c.OldRank, c.NewRank, c.OldScore.NumSolved, c.NewScore.NumSolved, c.OldScore.TotalTime,
+		c.NewScore.TotalTime, strings.Join(problems, ""))
+}
+
+// NewlySolved returns whether the problem was unsolved on the old scoreboard and solved on the new one
+func (c ProblemChange) NewlySolved() bool {
+	return !c.Old.Solved && c.New.Solved
+}
+
+// PendingChange returns the change in the number of pending submissions
+func (c ProblemChange) PendingChange() int {
+	return c.New.NumPending - c.Old.NumPending
+}
+
+func (c ProblemChange) String() string {
+	return fmt.Sprintf(`
+         problem id: %v
+         num judged: %v -> %v
+        num pending: %v -> %v
+             solved: %v -> %v
+               time: %v -> %v
+`, c.ProblemId, c.Old.NumJudged, c.New.NumJudged, c.Old.NumPending, c.New.NumPending, c.Old.Solved, c.New.Solved,
+		c.Old.Time, c.New.Time)
+}
@@ -0,0 +1,105 @@
+package interactor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffScoreboards(t *testing.T) {
+	before := Scoreboard{
+		ContestTime: ApiRelTime(60),
+		Rows: []Row{
+			{Rank: 1, TeamId: "t1", Score: Score{NumSolved: 1, TotalTime: 20}, Problems: []ScoreProblem{
+				{ProblemId: "a", NumJudged: 1, Solved: true, Time: 20},
+				{ProblemId: "b", NumPending: 1},
+			}},
+			{Rank: 2, TeamId: "t2", Problems: []ScoreProblem{
+				{ProblemId: "a", NumPending: 1},
+				{ProblemId: "b"},
+			}},
+			{Rank: 2, TeamId: "t3", Problems: []ScoreProblem{
+				{ProblemId: "a"},
+				{ProblemId: "b"},
+			}},
+		},
+	}
+
+	after := Scoreboard{
+		ContestTime: ApiRelTime(120),
+		Rows: []Row{
+			{Rank: 1, TeamId: "t2", Score: Score{NumSolved: 1, TotalTime: 10}, Problems: []ScoreProblem{
+				{ProblemId: "a", NumJudged: 1, Solved: true, Time: 10},
+				{ProblemId: "b"},
+			}},
+			{Rank: 2, TeamId: "t1", Score: Score{NumSolved: 1, TotalTime: 20}, Problems: []ScoreProblem{
+				{ProblemId: "a", NumJudged: 1, Solved: true, Time: 20},
+				{ProblemId: "b", NumJudged: 1},
+			}},
+			{Rank: 3, TeamId: "t4", Problems: []ScoreProblem{
+				{ProblemId: "a"},
+				{ProblemId: "b"},
+			}},
+		},
+	}
+
+	t.Run("equal", func(t *testing.T) {
+		diff := DiffScoreboards(before, before)
+		assert.True(t, diff.Empty())
+		assert.Empty(t, diff.Changed)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		diff := DiffScoreboards(before, after)
+		assert.False(t, diff.Empty())
+		assert.EqualValues(t, ApiRelTime(60), diff.OldContestTime)
+		assert.EqualValues(t, ApiRelTime(120), diff.NewContestTime)
+
+		if assert.Len(t, diff.Added, 1) {
+			assert.EqualValues(t, "t4", diff.Added[0].TeamId)
+		}
+		if assert.Len(t, diff.Removed, 1) {
+			assert.EqualValues(t, "t3", diff.Removed[0].TeamId)
+		}
+
+		if !assert.Len(t, diff.Changed, 2) {
+			return
+		}
+
+		t2 := diff.Changed[0]
+		assert.EqualValues(t, "t2", t2.TeamId)
+		assert.EqualValues(t, 1, t2.RankChange())
+		assert.EqualValues(t, []Identifier{"a"}, t2.NewlySolved())
+		if assert.Len(t, t2.Problems, 1) {
+			assert.EqualValues(t, -1, t2.Problems[0].PendingChange())
+		}
+
+		t1 := diff.Changed[1]
+		assert.EqualValues(t, "t1", t1.TeamId)
+		assert.EqualValues(t, -1, t1.RankChange())
+		assert.Empty(t, t1.NewlySolved())
+		if assert.Len(t, t1.Problems, 1) {
+			assert.EqualValues(t, "b", t1.Problems[0].ProblemId)
+			assert.EqualValues(t, -1, t1.Problems[0].PendingChange())
+			assert.EqualValues(t, 1, t1.Problems[0].New.NumJudged)
+		}
+
+		assert.Contains(t, diff.String(), "rank: 2 -> 1")
+	})
+
+	t.Run("removed-problem", func(t *testing.T) {
+		// Problem b is no longer on the scoreboard, only its pending submission of team 1 is a change
+		removed := Scoreboard{Rows: make([]Row, len(before.Rows))}
+		for k, row := range before.Rows {
+			row.Problems = row.Problems[:1]
+			removed.Rows[k] = row
+		}
+
+		diff := DiffScoreboards(before, removed)
+		if assert.Len(t, diff.Changed, 1) && assert.Len(t, diff.Changed[0].Problems, 1) {
+			assert.EqualValues(t, "t1", diff.Changed[0].TeamId)
+			assert.EqualValues(t, "b", diff.Changed[0].Problems[0].ProblemId)
+			assert.EqualValues(t, -1, diff.Changed[0].Problems[0].PendingChange())
+		}
+	})
+}
@@ -0,0 +1,281 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// Shadow compares a primary CCS with a CCS shadowing it. Submissions are matched on id, submissions that can not
+	// be matched on id are matched on team, problem and contest time. The judgements of matched submissions and the
+	// scoreboards of both systems are compared.
+	Shadow struct {
+		Primary ContestApi
+		Shadow  ContestApi
+		// TimeTolerance is the maximum difference in contest time between two submissions matched on team and problem
+		TimeTolerance time.Duration
+	}
+
+	// ShadowSnapshot contains the data of a single CCS used for comparison
+	ShadowSnapshot struct {
+		Submissions []Submission
+		Judgements  []Judgement
+		Scoreboard  Scoreboard
+	}
+
+	// ShadowReport is the result of comparing a primary CCS with its shadow
+	ShadowReport struct {
+		// Matched is the number of submissions found in both systems
+		Matched int
+		// Discrepancies contains the differences in submissions and judgements, ordered by primary contest time
+		Discrepancies []Discrepancy
+		// Scoreboard contains the differences between the scoreboard of the primary and the shadow
+		Scoreboard ScoreboardDiff
+	}
+
+	// DiscrepancyKind is the kind of difference found between a primary CCS and its shadow
+	DiscrepancyKind string
+
+	// Discrepancy is a difference in submissions or judgements between a primary CCS and its shadow. The submission
+	// of a system is nil when it is missing from that system.
+	Discrepancy struct {
+		Kind             DiscrepancyKind
+		Primary          *Submission
+		Shadow           *Submission
+		PrimaryJudgement string
+		ShadowJudgement  string
+	}
+)
+
+const (
+	// DiscrepancyMissingInShadow indicates a submission of the primary that could not be found in the shadow
+	DiscrepancyMissingInShadow DiscrepancyKind = "missing-in-shadow"
+	// DiscrepancyMissingInPrimary indicates a submission of the shadow that could not be found in the primary
+	DiscrepancyMissingInPrimary DiscrepancyKind = "missing-in-primary"
+	// DiscrepancyJudgement indicates a submission which is judged differently by both systems
+	DiscrepancyJudgement DiscrepancyKind = "judgement"
+	// DiscrepancyPending indicates a submission which is judged by only one of the systems
+	DiscrepancyPending DiscrepancyKind = "pending"
+
+	// DefaultShadowTimeTolerance is the TimeTolerance used by NewShadow
+	DefaultShadowTimeTolerance = time.Second
+)
+
+// NewShadow constructs a Shadow comparing primary with shadow
+func NewShadow(primary, shadow ContestApi) *Shadow {
+	return &Shadow{
+		Primary:       primary,
+		Shadow:        shadow,
+		TimeTolerance: DefaultShadowTimeTolerance,
+	}
+}
+
+// Compare retrieves the current data of both systems and compares them
+func (s *Shadow) Compare() (ShadowReport, error) {
+	return s.CompareContext(context.Background())
+}
+
+func (s *Shadow) CompareContext(ctx context.Context) (ShadowReport, error) {
+	primary, err := TakeShadowSnapshot(ctx, s.Primary)
+	if err != nil {
+		return ShadowReport{}, fmt.Errorf("could not retrieve primary; %w", err)
+	}
+
+	shadow, err := TakeShadowSnapshot(ctx, s.Shadow)
+	if err != nil {
+		return ShadowReport{}, fmt.Errorf("could not retrieve shadow; %w", err)
+	}
+
+	return CompareShadow(primary, shadow, s.TimeTolerance), nil
+}
+
+// TakeShadowSnapshot retrieves the data used for comparison from api
+func TakeShadowSnapshot(ctx context.Context, api ContestApi) (ShadowSnapshot, error) {
+	var snapshot ShadowSnapshot
+	var err error
+
+	if snapshot.Submissions, err = api.SubmissionsContext(ctx); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Judgements, err = api.JudgementsContext(ctx); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Scoreboard, err = api.ScoreboardContext(ctx); err != nil {
+		return snapshot, err
+	}
+
+	return snapshot, nil
+}
+
+// CompareShadow compares the snapshots of a primary CCS and its shadow. Submissions are matched on id when their team
+// and problem are the same, otherwise on team and problem when their contest times differ by at most tolerance.
+func CompareShadow(primary, shadow ShadowSnapshot, tolerance time.Duration) ShadowReport {
+	report := ShadowReport{
+		Scoreboard: DiffScoreboards(primary.Scoreboard, shadow.Scoreboard),
+	}
+
+	primaryJudgements := currentJudgements(primary.Judgements)
+	shadowJudgements := currentJudgements(shadow.Judgements)
+
+	shadowById := make(map[string]int, len(shadow.Submissions))
+	for k, sub := range shadow.Submissions {
+		shadowById[sub.Id] = k
+	}
+
+	// First match on id, then match the remaining submissions on team, problem and time. Independent systems can use
+	// the same ids for unrelated submissions, so an id only matches when the team and problem do as well.
+	matched := make(map[int]bool, len(shadow.Submissions))
+	pairs := make(map[int]int, len(primary.Submissions))
+	for k, sub := range primary.Submissions {
+		m, ok := shadowById[sub.Id]
+		if !ok || sub.Id == "" || matched[m] {
+			continue
+		}
+
+		if candidate := shadow.Submissions[m]; candidate.TeamId == sub.TeamId && candidate.ProblemId == sub.ProblemId {
+			pairs[k] = m
+			matched[m] = true
+		}
+	}
+
+	for k, sub := range primary.Submissions {
+		if _, ok := pairs[k]; ok {
+			continue
+		}
+
+		best := -1
+		var bestDiff time.Duration
+		for m, candidate := range shadow.Submissions {
+			if matched[m] || candidate.TeamId != sub.TeamId || candidate.ProblemId != sub.ProblemId {
+				continue
+			}
+
+			diff := (candidate.ContestTime - sub.ContestTime).Duration()
+			if diff < 0 {
+				diff = -diff
+			}
+
+			if diff <= tolerance && (best < 0 || diff < bestDiff) {
+				best, bestDiff = m, diff
+			}
+		}
+
+		if best >= 0 {
+			pairs[k] = best
+			matched[best] = true
+		}
+	}
+
+	for k := range primary.Submissions {
+		p := &primary.Submissions[k]
+		m, ok := pairs[k]
+		if !ok {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{Kind: DiscrepancyMissingInShadow, Primary: p})
+			continue
+		}
+
+		report.Matched++
+		sh := &shadow.Submissions[m]
+		d := Discrepancy{
+			Primary:          p,
+			Shadow:           sh,
+			PrimaryJudgement: primaryJudgements[p.Id],
+			ShadowJudgement:  shadowJudgements[sh.Id],
+		}
+
+		switch {
+		case d.PrimaryJudgement == d.ShadowJudgement:
+			continue
+		case d.PrimaryJudgement == "" || d.ShadowJudgement == "":
+			d.Kind = DiscrepancyPending
+		default:
+			d.Kind = DiscrepancyJudgement
+		}
+
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+
+	for m := range shadow.Submissions {
+		if !matched[m] {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{Kind: DiscrepancyMissingInPrimary, Shadow: &shadow.Submissions[m]})
+		}
+	}
+
+	sort.SliceStable(report.Discrepancies, func(a, b int) bool {
+		return report.Discrepancies[a].submission().ContestTime < report.Discrepancies[b].submission().ContestTime
+	})
+	return report
+}
+
+// currentJudgements returns the judgement type of the last finished judgement of every submission
+func currentJudgements(judgements []Judgement) map[string]string {
+	current := make(map[string]Judgement, len(judgements))
+	for _, j := range judgements {
+		if j.JudgementTypeId == "" {
+			continue
+		}
+
+		if c, ok := current[j.SubmissionId]; !ok || c.StartContestTime <= j.StartContestTime {
+			current[j.SubmissionId] = j
+		}
+	}
+
+	types := make(map[string]string, len(current))
+	for id, j := range current {
+		types[id] = j.JudgementTypeId
+	}
+
+	return types
+}
+
+// Consistent returns whether no differences were found
+func (r ShadowReport) Consistent() bool {
+	return len(r.Discrepancies) == 0 && r.Scoreboard.Empty()
+}
+
+func (r ShadowReport) String() string {
+	discrepancies := make([]string, len(r.Discrepancies))
+	for k, d := range r.Discrepancies {
+		discrepancies[k] = d.String()
+	}
+
+	return fmt.Sprintf(`
+      matched: %v
+discrepancies: %v
+   scoreboard: %v
+`, r.Matched, strings.Join(discrepancies, ""), r.Scoreboard)
+}
+
+// submission returns the primary submission, or the shadow submission when it is missing from the primary
+func (d Discrepancy) submission() *Submission {
+	if d.Primary != nil {
+		return d.Primary
+	}
+
+	return d.Shadow
+}
+
+func (d Discrepancy) String() string {
+	var primaryId, shadowId string
+	if d.Primary != nil {
+		primaryId = d.Primary.Id
+	}
+	if d.Shadow != nil {
+		shadowId = d.Shadow.Id
+	}
+
+	s := d.submission()
+	return fmt.Sprintf(`
+              kind: %v
+           team id: %v
+        problem id: %v
+      contest time: %v
+        primary id: %v
+         shadow id: %v
+ primary judgement: %v
+  shadow judgement: %v
+`, d.Kind, s.TeamId, s.ProblemId, s.ContestTime, primaryId, shadowId, d.PrimaryJudgement, d.ShadowJudgement)
+}
@@ -0,0 +1,96 @@
+package interactor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShadow(t *testing.T) {
+	primary := NewMockServer(DefaultMockData())
+	defer primary.Close()
+
+	// The shadow judged s1 differently, uses its own id for s3, has an extra submission and an outdated scoreboard
+	data := DefaultMockData()
+	c := &data.Contests[0]
+	c.Judgements[0].JudgementTypeId = "TLE"
+	c.Submissions[2].Id = "x3"
+	c.Submissions[2].ContestTime += ApiRelTime(500 * time.Millisecond)
+	c.Judgements[2].SubmissionId = "x3"
+	c.Submissions = append(c.Submissions, Submission{Id: "s4", TeamId: "2", ProblemId: "circuitdesign",
+		LanguageId: "java", ContestTime: ApiRelTime(40 * time.Minute)})
+	c.Scoreboard.Rows = c.Scoreboard.Rows[:1]
+	shadow := NewMockServer(data)
+	defer shadow.Close()
+
+	login := func(url string) ContestApi {
+		api, err := ContestInteractor(url, "admin", "admin", "nwerc18", false)
+		assert.Nil(t, err)
+		return api
+	}
+
+	t.Run("consistent", func(t *testing.T) {
+		report, err := NewShadow(login(primary.URL), login(primary.URL)).Compare()
+		assert.Nil(t, err)
+		assert.True(t, report.Consistent())
+		assert.EqualValues(t, 3, report.Matched)
+	})
+
+	t.Run("discrepancies", func(t *testing.T) {
+		report, err := NewShadow(login(primary.URL), login(shadow.URL)).Compare()
+		assert.Nil(t, err)
+		assert.False(t, report.Consistent())
+		assert.EqualValues(t, 3, report.Matched)
+
+		if assert.Len(t, report.Discrepancies, 2) {
+			assert.EqualValues(t, DiscrepancyJudgement, report.Discrepancies[0].Kind)
+			assert.EqualValues(t, "s1", report.Discrepancies[0].Primary.Id)
+			assert.EqualValues(t, "WA", report.Discrepancies[0].PrimaryJudgement)
+			assert.EqualValues(t, "TLE", report.Discrepancies[0].ShadowJudgement)
+
+			assert.EqualValues(t, DiscrepancyMissingInPrimary, report.Discrepancies[1].Kind)
+			assert.Nil(t, report.Discrepancies[1].Primary)
+			assert.EqualValues(t, "s4", report.Discrepancies[1].Shadow.Id)
+		}
+
+		if assert.Len(t, report.Scoreboard.Removed, 1) {
+			assert.EqualValues(t, "1", report.Scoreboard.Removed[0].TeamId)
+		}
+	})
+
+	t.Run("tolerance", func(t *testing.T) {
+		s := NewShadow(login(primary.URL), login(shadow.URL))
+		s.TimeTolerance = 0
+
+		report, err := s.Compare()
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, report.Matched)
+		assert.Len(t, report.Discrepancies, 4)
+	})
+}
+
+func TestCompareShadowIds(t *testing.T) {
+	// Both systems number their submissions, the same id is used for unrelated submissions
+	primary := ShadowSnapshot{
+		Submissions: []Submission{{Id: "1", TeamId: "t1", ProblemId: "a", ContestTime: ApiRelTime(time.Minute)}},
+		Judgements:  []Judgement{{Id: "j1", SubmissionId: "1", JudgementTypeId: "AC"}},
+	}
+	shadow := ShadowSnapshot{
+		Submissions: []Submission{
+			{Id: "1", TeamId: "t2", ProblemId: "b", ContestTime: ApiRelTime(30 * time.Second)},
+			{Id: "2", TeamId: "t1", ProblemId: "a", ContestTime: ApiRelTime(time.Minute)},
+		},
+		Judgements: []Judgement{
+			{Id: "j1", SubmissionId: "1", JudgementTypeId: "WA"},
+			{Id: "j2", SubmissionId: "2", JudgementTypeId: "AC"},
+		},
+	}
+
+	report := CompareShadow(primary, shadow, time.Second)
+	assert.EqualValues(t, 1, report.Matched)
+	if assert.Len(t, report.Discrepancies, 1) {
+		assert.EqualValues(t, DiscrepancyMissingInPrimary, report.Discrepancies[0].Kind)
+		assert.EqualValues(t, "t2", report.Discrepancies[0].Shadow.TeamId)
+	}
+}
@@ -0,0 +1,35 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DownloadSubmissionFiles downloads the files of submissions using api and unpacks them into dir, using a directory per
+// submission named after its id. Downloading stops at the first submission that fails.
+func DownloadSubmissionFiles(ctx context.Context, api ContestApi, submissions []Submission, dir string) error {
+	for _, submission := range submissions {
+		// The id is used as a directory name, so it must not be able to escape dir
+		if !validPathElement(submission.Id) {
+			return fmt.Errorf("invalid submission id %q", submission.Id)
+		}
+
+		files, err := api.SubmissionFilesContext(ctx, submission)
+		if err != nil {
+			return err
+		}
+
+		if err := files.Extract(filepath.Join(dir, submission.Id)); err != nil {
+			return fmt.Errorf("could not extract files of submission %v; %w", submission.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// validPathElement returns whether name can be used as a single element of a path
+func validPathElement(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
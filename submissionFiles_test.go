@@ -0,0 +1,126 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmissionFiles(t *testing.T) {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	api, err := ContestInteractor(server.URL, "admin", "admin", "nwerc18", false)
+	assert.Nil(t, err)
+
+	t.Run("relative-href", func(t *testing.T) {
+		submission, err := api.SubmissionById("s3")
+		assert.Nil(t, err)
+
+		files, err := api.SubmissionFiles(submission)
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"Main.java"}, files.Names())
+	})
+
+	t.Run("absolute-href", func(t *testing.T) {
+		files, err := api.SubmissionFiles(Submission{Id: "s1", Files: []FileReference{
+			{Href: server.URL + "/contests/nwerc18/submissions/s1/files"},
+		}})
+		assert.Nil(t, err)
+
+		contents, ok := files.Contents("main.cpp")
+		assert.True(t, ok)
+		assert.EqualValues(t, "int main() { return 1; }", string(contents))
+	})
+
+	t.Run("no-href", func(t *testing.T) {
+		files, err := api.SubmissionFiles(Submission{Id: "s2"})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []string{"main.cpp"}, files.Names())
+	})
+
+	t.Run("posted", func(t *testing.T) {
+		team, err := ContestInteractor(server.URL, "team", "team", "nwerc18", false)
+		assert.Nil(t, err)
+
+		var files LocalFileReference
+		_ = files.FromString("main.py", "print(42)")
+		submission, err := team.PostSubmission("brexit", "python3", "", files)
+		assert.Nil(t, err)
+
+		downloaded, err := team.SubmissionFiles(submission)
+		assert.Nil(t, err)
+		assert.EqualValues(t, files, downloaded)
+
+		// Teams can not download the files of other teams
+		_, err = team.SubmissionFiles(Submission{Id: "s3"})
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("size-limits", func(t *testing.T) {
+		defer func(download, unzipped int64) {
+			MaxDownloadSize, MaxUnzippedSize = download, unzipped
+		}(MaxDownloadSize, MaxUnzippedSize)
+
+		MaxUnzippedSize = 10
+		_, err := api.SubmissionFiles(Submission{Id: "s1"})
+		assert.True(t, errors.Is(err, ErrFileTooLarge))
+
+		MaxUnzippedSize, MaxDownloadSize = 1024, 10
+		_, err = api.SubmissionFiles(Submission{Id: "s1"})
+		assert.True(t, errors.Is(err, ErrFileTooLarge))
+	})
+}
+
+func TestFromZipLimit(t *testing.T) {
+	defer func(unzipped int64) { MaxUnzippedSize = unzipped }(MaxUnzippedSize)
+
+	var files LocalFileReference
+	_ = files.FromString("a.txt", "123456")
+	_ = files.FromString("b.txt", "123456")
+	data, err := files.Zip()
+	assert.Nil(t, err)
+
+	// Every file is within the limit, but together they are not
+	MaxUnzippedSize = 10
+	var unzipped LocalFileReference
+	assert.True(t, errors.Is(unzipped.FromZip(data), ErrFileTooLarge))
+
+	MaxUnzippedSize = 12
+	unzipped = LocalFileReference{}
+	assert.Nil(t, unzipped.FromZip(data))
+	assert.EqualValues(t, files, unzipped)
+}
+
+func TestDownloadSubmissionFiles(t *testing.T) {
+	server := NewMockServer(DefaultMockData())
+	defer server.Close()
+
+	api, err := ContestInteractor(server.URL, "admin", "admin", "nwerc18", false)
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "submissions")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	submissions, err := api.Submissions()
+	assert.Nil(t, err)
+	assert.Nil(t, DownloadSubmissionFiles(context.Background(), api, submissions, dir))
+
+	for _, submission := range submissions {
+		_, err := os.Stat(filepath.Join(dir, submission.Id))
+		assert.Nil(t, err)
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "s3", "Main.java"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(contents), "class Main")
+
+	err = DownloadSubmissionFiles(context.Background(), api, []Submission{{Id: "../s1"}}, dir)
+	assert.NotNil(t, err)
+}
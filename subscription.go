@@ -0,0 +1,234 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type (
+	// EventPosition is a position in the event feed, it can be persisted to resume reading the feed at a later time.
+	// Token is used by 2022-07+ servers, Id by older servers.
+	EventPosition struct {
+		Token string `json:"token,omitempty"`
+		Id    string `json:"id,omitempty"`
+	}
+
+	// Subscription reads the event feed of a contest and transparently reconnects when the feed is interrupted,
+	// resuming after the last event that was delivered.
+	Subscription struct {
+		// RetryDelay is the time waited before reconnecting after the feed was interrupted
+		RetryDelay time.Duration
+		// MaxRetries is the number of consecutive failed connection attempts after which Next gives up, 0 for no limit.
+		// Connections that end without delivering an event count as failed attempts.
+		MaxRetries int
+
+		ctx      context.Context
+		open     func(since EventPosition) (*EventReader, error)
+		mu       sync.Mutex
+		reader   *EventReader
+		position EventPosition
+		// received is whether the current reader delivered an event
+		received bool
+		// ends is whether the feed ends at its last event instead of being interrupted, such as the feed of a package
+		ends   bool
+		done   chan struct{}
+		closed bool
+	}
+)
+
+var ErrSubscriptionClosed = errors.New("subscription closed")
+
+func newSubscription(ctx context.Context, since EventPosition, open func(since EventPosition) (*EventReader, error)) *Subscription {
+	s := &Subscription{
+		RetryDelay: 5 * time.Second,
+		MaxRetries: 10,
+		ctx:        ctx,
+		open:       open,
+		position:   since,
+		done:       make(chan struct{}),
+	}
+
+	// Close the subscription as soon as the context is done, this also interrupts a blocked read
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				_ = s.Close()
+			case <-s.done:
+			}
+		}()
+	}
+
+	return s
+}
+
+// Next blocks until the next event is available and returns it. When the feed is interrupted it is reopened after
+// the last delivered event. An error is returned when the subscription is closed, when reconnecting failed or the
+// feed ended without delivering an event MaxRetries times in a row, when the server rejects the request or when an
+// event could not be parsed. When the context of the subscription is done, its error is returned. A feed that ends,
+// such as the feed of a contest package, is not reopened and io.EOF is returned after its last event.
+func (s *Subscription) Next() (Event, error) {
+	var failures int
+	for {
+		reader, err := s.connect()
+		if err != nil {
+			if err == ErrSubscriptionClosed {
+				return Event{}, s.closedErr()
+			}
+
+			// Retrying does not help when the feed is not accessible
+			var apiErr *APIError
+			if errors.Is(err, ErrNotFound) || (errors.As(err, &apiErr) && !apiErr.Temporary()) {
+				return Event{}, err
+			}
+
+			failures++
+			if s.MaxRetries > 0 && failures >= s.MaxRetries {
+				return Event{}, err
+			}
+
+			if !s.wait() {
+				return Event{}, s.closedErr()
+			}
+
+			continue
+		}
+
+		line, err := reader.nextLine()
+		if s.ends && errors.Is(err, io.EOF) {
+			s.disconnect(reader)
+			return Event{}, io.EOF
+		}
+
+		if err != nil {
+			// The feed was interrupted, either the stream ended or the connection broke. Reconnect after waiting.
+			if !s.disconnect(reader) {
+				// A server that keeps answering without sending events should not be reconnected to forever
+				failures++
+				if s.MaxRetries > 0 && failures >= s.MaxRetries {
+					return Event{}, fmt.Errorf("event feed ended %d times without delivering an event; %w", failures, err)
+				}
+			}
+
+			if !s.wait() {
+				return Event{}, s.closedErr()
+			}
+
+			continue
+		}
+
+		e, err := ParseEvent(line)
+		if err != nil {
+			return e, err
+		}
+
+		s.mu.Lock()
+		s.received = true
+		if e.Token != "" {
+			s.position.Token = e.Token
+		}
+		if e.Id != "" {
+			s.position.Id = e.Id
+		}
+		s.mu.Unlock()
+
+		return e, nil
+	}
+}
+
+// Position returns the position of the last delivered event, which can be used to resume the feed with Subscribe
+func (s *Subscription) Position() EventPosition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.position
+}
+
+// Close closes the subscription, a blocked call to Next will return ErrSubscriptionClosed
+func (s *Subscription) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	close(s.done)
+	if s.reader != nil {
+		return s.reader.Close()
+	}
+
+	return nil
+}
+
+// closedErr returns the error of the context when it caused the subscription to be closed, ErrSubscriptionClosed
+// otherwise
+func (s *Subscription) closedErr() error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	return ErrSubscriptionClosed
+}
+
+// connect returns the current reader, opening the feed if there is none
+func (s *Subscription) connect() (*EventReader, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrSubscriptionClosed
+	}
+
+	reader, position := s.reader, s.position
+	s.mu.Unlock()
+
+	if reader != nil {
+		return reader, nil
+	}
+
+	// Open the feed without holding the lock, such that Close is never blocked by a slow server
+	reader, err := s.open(position)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		_ = reader.Close()
+		return nil, ErrSubscriptionClosed
+	}
+
+	s.reader = reader
+	s.received = false
+	return reader, nil
+}
+
+// disconnect closes reader, if it is still the current reader, and returns whether it delivered an event
+func (s *Subscription) disconnect(reader *EventReader) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.reader == reader {
+		_ = s.reader.Close()
+		s.reader = nil
+	}
+
+	return s.received
+}
+
+// wait waits RetryDelay, returns false when the subscription is closed in the meantime
+func (s *Subscription) wait() bool {
+	select {
+	case <-s.done:
+		return false
+	case <-time.After(s.RetryDelay):
+		return true
+	}
+}
@@ -0,0 +1,113 @@
+package interactor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscription(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		tokens []string
+	)
+
+	// The server sends two events per connection and then drops it, mimicking a server restart
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/event-feed":
+			mu.Lock()
+			tokens = append(tokens, r.URL.Query().Get("since_token"))
+			start := len(tokens)*2 - 2
+			mu.Unlock()
+
+			for k := start; k < start+2; k++ {
+				_, _ = fmt.Fprintf(w, `{"type":"problems","id":"p%d","data":{"id":"p%d"},"token":"%d"}`+"\n", k, k, k)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ser.Close()
+
+	api, err := ContestInteractor(ser.URL, "", "", "test", false)
+	assert.Nil(t, err)
+
+	sub := api.Subscribe(EventPosition{})
+	sub.RetryDelay = time.Millisecond
+	defer sub.Close()
+
+	for k := 0; k < 5; k++ {
+		e, err := sub.Next()
+		assert.Nil(t, err)
+		assert.EqualValues(t, fmt.Sprintf("p%d", k), e.ObjectId)
+		assert.EqualValues(t, fmt.Sprint(k), sub.Position().Token)
+	}
+
+	mu.Lock()
+	assert.EqualValues(t, []string{"", "1", "3"}, tokens)
+	mu.Unlock()
+
+	t.Run("closed", func(t *testing.T) {
+		assert.Nil(t, sub.Close())
+		_, err := sub.Next()
+		assert.Equal(t, ErrSubscriptionClosed, err)
+	})
+
+	t.Run("max-retries", func(t *testing.T) {
+		sub := api.Subscribe(EventPosition{Token: "unknown"})
+		sub.RetryDelay = time.Millisecond
+		sub.MaxRetries = 2
+		defer sub.Close()
+
+		ser.Close()
+		_, err := sub.Next()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSubscriptionEmptyFeed(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		connections int
+	)
+
+	// The server accepts every connection, but never sends an event
+	ser := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contests/test":
+			_, _ = w.Write([]byte(`{"id":"test","name":"Test contest"}`))
+		case "/contests/test/event-feed":
+			mu.Lock()
+			connections++
+			mu.Unlock()
+
+			_, _ = w.Write([]byte("\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ser.Close()
+
+	api, err := ContestInteractor(ser.URL, "", "", "test", false)
+	assert.Nil(t, err)
+
+	sub := api.Subscribe(EventPosition{})
+	sub.RetryDelay = time.Millisecond
+	sub.MaxRetries = 3
+	defer sub.Close()
+
+	_, err = sub.Next()
+	assert.NotNil(t, err)
+
+	mu.Lock()
+	assert.EqualValues(t, 3, connections)
+	mu.Unlock()
+}